@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/ethclient/gethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// createAccessList 调用 eth_createAccessList 为调用生成访问列表 (EIP-2930)
+func createAccessList(client *ethclient.Client, msg ethereum.CallMsg) (types.AccessList, error) {
+	list, _, errMsg, err := gethclient.New(client.Client()).CreateAccessList(context.Background(), msg)
+	if err != nil {
+		return nil, err
+	}
+	if errMsg != "" {
+		return nil, errors.New(errMsg)
+	}
+	if list == nil {
+		return types.AccessList{}, nil
+	}
+	return *list, nil
+}
+
+// isMethodNotFound 判断节点是否不支持该 RPC 方法
+func isMethodNotFound(err error) bool {
+	var rpcErr rpc.Error
+	return errors.As(err, &rpcErr) && rpcErr.ErrorCode() == -32601
+}
+
+// signAccessListTx 使用交易选项中的 nonce、gas 价格和 gas 限制构造并签名带访问列表的交易，不发送
+func signAccessListTx(client *ethclient.Client, auth *bind.TransactOpts, msg ethereum.CallMsg, accessList types.AccessList) (*types.Transaction, error) {
+	ctx := context.Background()
+	chainID, err := client.ChainID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("获取链 ID 失败: %v", err)
+	}
+	var nonce uint64
+	if auth.Nonce != nil {
+		nonce = auth.Nonce.Uint64()
+	} else if nonce, err = client.PendingNonceAt(ctx, auth.From); err != nil {
+		return nil, fmt.Errorf("获取 nonce 失败: %v", err)
+	}
+	gasPrice := auth.GasPrice
+	if gasPrice == nil {
+		if gasPrice, err = client.SuggestGasPrice(ctx); err != nil {
+			return nil, fmt.Errorf("获取网络 gas 价格失败: %v", err)
+		}
+	}
+	value := msg.Value
+	if value == nil {
+		value = new(big.Int)
+	}
+	tx := types.NewTx(&types.AccessListTx{
+		ChainID:    chainID,
+		Nonce:      nonce,
+		GasPrice:   gasPrice,
+		Gas:        auth.GasLimit,
+		To:         msg.To,
+		Value:      value,
+		Data:       msg.Data,
+		AccessList: accessList,
+	})
+	signedTx, err := auth.Signer(auth.From, tx)
+	if err != nil {
+		return nil, fmt.Errorf("签名交易失败: %v", err)
+	}
+	return signedTx, nil
+}
+
+// estimateWithAccessList 为调用生成访问列表并重新估算 gas，返回访问列表、普通估算值和使用访问列表后的估算值。
+// plainGas 为 0 时先进行一次普通估算
+func estimateWithAccessList(client *ethclient.Client, msg ethereum.CallMsg, plainGas uint64) (types.AccessList, uint64, uint64, error) {
+	ctx := context.Background()
+	if plainGas == 0 {
+		gas, err := client.EstimateGas(ctx, msg)
+		if err != nil {
+			return nil, 0, 0, fmt.Errorf("估算 gas 失败: %v", err)
+		}
+		plainGas = gas
+	}
+	accessList, err := createAccessList(client, msg)
+	if err != nil {
+		return nil, plainGas, 0, err
+	}
+	msg.AccessList = accessList
+	listGas, err := client.EstimateGas(ctx, msg)
+	if err != nil {
+		return nil, plainGas, 0, fmt.Errorf("使用访问列表估算 gas 失败: %v", err)
+	}
+	return accessList, plainGas, listGas, nil
+}
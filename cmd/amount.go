@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+)
+
+// weiToEther 将 Wei 转换为以 ETH/BNB 为单位的浮点数，仅用于日志展示
+func weiToEther(wei *big.Int) float64 {
+	value, _ := new(big.Float).Quo(new(big.Float).SetInt(wei), big.NewFloat(1e18)).Float64()
+	return value
+}
+
+// formatUnits 将最小单位的金额按 decimals 位小数精确格式化为十进制字符串
+func formatUnits(amount *big.Int, decimals int) string {
+	if decimals <= 0 {
+		return amount.String()
+	}
+	divisor := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)
+	value := new(big.Rat).SetFrac(amount, divisor).FloatString(decimals)
+	value = strings.TrimRight(value, "0")
+	return strings.TrimSuffix(value, ".")
+}
+
+// formatEther 将 Wei 精确格式化为以 ETH/BNB 为单位的十进制字符串
+func formatEther(wei *big.Int) string {
+	return formatUnits(wei, 18)
+}
+
+// DisplayUnit 是日志中转账金额的展示单位，Symbol 为空时按 18 位小数的 ETH 展示
+type DisplayUnit struct {
+	Symbol   string
+	Decimals int
+}
+
+// Format 按展示单位格式化金额，例如 "12.5 USDT"
+func (u DisplayUnit) Format(amount *big.Int) string {
+	if u.Symbol == "" {
+		return formatEther(amount) + " ETH"
+	}
+	return formatUnits(amount, u.Decimals) + " " + u.Symbol
+}
+
+// amountUnits 是金额字符串支持的单位后缀及其对应的 Wei 数量
+var amountUnits = []struct {
+	suffix string
+	wei    *big.Int
+}{
+	{"gwei", big.NewInt(1e9)},
+	{"wei", big.NewInt(1)},
+	{"ether", big.NewInt(1e18)},
+	{"eth", big.NewInt(1e18)},
+	{"bnb", big.NewInt(1e18)},
+}
+
+// decimalAmountPattern 是去掉分隔符和单位后允许的十进制金额格式，
+// 排除 big.Rat 也能解析的分数、十六进制（0x10）和二进制指数（1p4）等写法
+var decimalAmountPattern = regexp.MustCompile(`^[+-]?(\d+\.?\d*|\.\d+)(e[+-]?\d+)?$`)
+
+// parseAmount 解析用户输入的金额并转换为 Wei。
+// 支持千位分隔符（1,000.5）、下划线分隔（0.000_1）、科学计数法（1e-3）
+// 以及 wei/gwei/ether 单位后缀（1e18 wei），未带单位时按 ETH/BNB 处理。
+func parseAmount(value string) (*big.Int, error) {
+	normalized := strings.ToLower(strings.TrimSpace(value))
+	normalized = strings.NewReplacer(",", "", "_", "", " ", "").Replace(normalized)
+
+	unit := big.NewInt(1e18)
+	for _, u := range amountUnits {
+		if strings.HasSuffix(normalized, u.suffix) {
+			normalized = strings.TrimSuffix(normalized, u.suffix)
+			unit = u.wei
+			break
+		}
+	}
+	if !decimalAmountPattern.MatchString(normalized) {
+		return nil, fmt.Errorf("无法解析金额: %q", value)
+	}
+	if strings.HasPrefix(normalized, "-") {
+		return nil, fmt.Errorf("金额不能为负数: %q", value)
+	}
+
+	amount, ok := new(big.Rat).SetString(normalized)
+	if !ok {
+		return nil, fmt.Errorf("无法解析金额: %q", value)
+	}
+	amount.Mul(amount, new(big.Rat).SetInt(unit))
+	if !amount.IsInt() {
+		return nil, fmt.Errorf("金额 %q 不是整数 Wei", value)
+	}
+	return new(big.Int).Set(amount.Num()), nil
+}
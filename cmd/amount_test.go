@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string // 以 Wei 为单位，为空表示应返回错误
+	}{
+		{"1", "1000000000000000000"},
+		{"0.0001", "100000000000000"},
+		{"1,000", "1000000000000000000000"},
+		{"1,000.5", "1000500000000000000000"},
+		{"0.000_1", "100000000000000"},
+		{"1e-3", "1000000000000000"},
+		{"1E-3", "1000000000000000"},
+		{".5", "500000000000000000"},
+		{"+2", "2000000000000000000"},
+		{"1e18 wei", "1000000000000000000"},
+		{"1e18wei", "1000000000000000000"},
+		{"21000 wei", "21000"},
+		{"5 gwei", "5000000000"},
+		{"1.5 GWEI", "1500000000"},
+		{"2 ether", "2000000000000000000"},
+		{"2 eth", "2000000000000000000"},
+		{"0.1 bnb", "100000000000000000"},
+		{"  3  ", "3000000000000000000"},
+		{"0", "0"},
+
+		{"-1", ""},
+		{"-0.5 gwei", ""},
+		{"", ""},
+		{"wei", ""},
+		{"abc", ""},
+		{"1.2.3", ""},
+		{"1/2", ""},
+		{"0x10", ""},
+		{"0x10 wei", ""},
+		{"1p4", ""},
+		{"0b101", ""},
+		{"0o17", ""},
+		{"inf", ""},
+		{"1e", ""},
+		{"0.5 wei", ""},
+		{"1e-19", ""},
+	}
+	for _, tt := range tests {
+		got, err := parseAmount(tt.input)
+		if tt.want == "" {
+			if err == nil {
+				t.Errorf("parseAmount(%q) = %s, want error", tt.input, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parseAmount(%q) error: %v", tt.input, err)
+			continue
+		}
+		want, _ := new(big.Int).SetString(tt.want, 10)
+		if got.Cmp(want) != 0 {
+			t.Errorf("parseAmount(%q) = %s, want %s", tt.input, got, want)
+		}
+	}
+}
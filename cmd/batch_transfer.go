@@ -1,9 +1,11 @@
 package cmd
 
 import (
+	"AccountSplitting/lib"
 	"context"
 	"encoding/csv"
 	"fmt"
+	"io"
 	"log"
 	"math/big"
 	"os"
@@ -11,9 +13,9 @@ import (
 	"time"
 
 	"github.com/ethereum/go-ethereum"
-	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/spf13/cobra"
@@ -24,14 +26,47 @@ const batchTransferABI = `[{"inputs":[{"internalType":"address[]","name":"recipi
 
 // 配置结构体
 type Config struct {
-	RPCURL          string
-	ContractAddress string
-	CSVFilePath     string
-	AmountPerWallet *big.Int // 每个钱包转账金额（以 Wei 为单位）
-	GasLimit        uint64   // 如果大于 0，则使用固定值
-	GasPrice        *big.Int
-	MaxWallets      int        // 最大处理钱包数量，0 表示不限制
-	SenderWallet    WalletInfo // 新增：发送者钱包信息
+	RPCURL           string
+	ContractAddress  string
+	CSVFilePath      string
+	CSVFilePaths     []string // 多个接收者 CSV 文件，依次合并（设置后替代 CSVFilePath）
+	Dedupe           bool     // 合并多个 CSV 时按地址去重
+	RecipientsJSON   string   // 接收者 JSON 文件路径（包含每个接收者的金额），设置后替代 CSV
+	AmountPerWallet  *big.Int // 每个钱包转账金额（以 Wei 为单位）
+	GasLimit         uint64   // 如果大于 0，则使用固定值
+	GasPrice         *big.Int
+	FeeCaps          *feeCaps      // EIP-1559 费用上限，设置后替代 GasPrice 发送 EIP-1559 交易
+	MaxTotalValue    *big.Int      // 总转账金额上限，nil 表示不限制
+	GasBuffer        int           // 估算 gas 后增加的缓冲百分比，0 表示原样使用估算值
+	MaxWallets       int           // 最大处理钱包数量，0 表示不限制
+	ExpectRecipients int           // 应用 MaxWallets 后期望的接收者数量，不一致时中止，0 表示不校验
+	BatchSize        int           // 每批处理的钱包数量，0 表示使用 defaultBatchSize
+	ManifestPath     string        // 批次清单文件，可覆盖指定批次的金额或跳过批次（优先级高于接收者金额和 --amount）
+	SenderWallet     WalletInfo    // 新增：发送者钱包信息
+	StartNonce       *uint64       // 起始 nonce，nil 表示由节点自动获取
+	SkipFunded       bool          // 跳过余额已达到转账金额的接收者
+	TokenAddress     string        // ERC-20 代币地址，设置后通过合约的 batchSendToken 分发代币而不是原生币
+	InfiniteApprove  bool          // 代币授权额度不足时授权 uint256 最大值，之后的运行无需再次 approve
+	TopUpTo          *big.Int      // 补足模式：将每个接收者余额补足到该值（以 Wei 为单位），nil 表示不启用
+	ContinueOnRevert bool          // 批次回滚时记录失败并继续处理后续批次
+	ReportFormat     string        // 转账报告格式 (csv, json, jsonl)
+	ABIJSON          string        // 自定义分账合约 ABI JSON，为空时使用内置的 batchSend ABI
+	Method           string        // 批量转账方法名，为空时使用 batchSend
+	OnEstimateFail   string        // 估算 gas 时调用回滚的处理方式 (abort, skip)，为空时中止
+	DumpRawPath      string        // 已签名交易的十六进制追加写入的文件，为空时不写入
+	ResumeFromTxHash string        // 从该已确认批次交易之后的接收者继续处理
+	Display          DisplayUnit   // 日志中转账金额的展示单位
+	VerifyLogs       bool          // 批次确认后解码合约事件，核对接收者数量和金额
+	WaitTimeout      time.Duration // 等待每批交易确认的超时时间，0 表示一直等待
+	Retries          int           // 每批遇到临时 RPC 错误时的最大重试次数
+	OnlyFailures     bool          // 只输出失败的批次和最终汇总
+	MaxTotalRetries  int           // 整个运行共享的重试次数上限，0 表示不限制
+	AccessList       bool          // 通过 eth_createAccessList 生成访问列表 (EIP-2930)，节省 gas 时随交易发送
+	CompareTo        string        // 上一次运行的 JSON/JSONL 报告，发送前输出接收者差异
+	DryRun           bool          // 只读取接收者并输出计划，不连接节点也不发送交易
+	VerifyAfter      bool          // 全部批次完成后核对每个接收者的余额增加量不少于已发送金额
+	GasBudget        *gasBudget    // 整个运行的手续费总额预算，nil 表示不限制
+	WebhookURL       string        // 每批交易确认后 POST 批次结果 JSON 的地址，为空时不回调
 }
 
 // 钱包信息结构体
@@ -39,17 +74,28 @@ type WalletInfo struct {
 	Address    string
 	PrivateKey string
 	Mnemonic   string
+	Amount     *big.Int // 可选 Amount 列（以 Wei 为单位），为 nil 时使用统一金额
 }
 
-// 读取 CSV 文件
+// stdinPath 是表示从标准输入读取 CSV 的文件路径
+const stdinPath = "-"
+
+// 读取 CSV 文件，路径为 "-" 时从标准输入读取
 func readWalletsFromCSV(filePath string) ([]WalletInfo, error) {
+	if filePath == stdinPath {
+		return readWalletsFromReader(os.Stdin)
+	}
 	file, err := os.Open(filePath)
 	if err != nil {
 		return nil, fmt.Errorf("打开 CSV 文件失败: %v", err)
 	}
 	defer file.Close()
+	return readWalletsFromReader(file)
+}
 
-	reader := csv.NewReader(file)
+// readWalletsFromReader 从任意输入读取钱包 CSV，并按列映射校验和解析
+func readWalletsFromReader(r io.Reader) ([]WalletInfo, error) {
+	reader := csv.NewReader(r)
 	records, err := reader.ReadAll()
 	if err != nil {
 		return nil, fmt.Errorf("读取 CSV 文件失败: %v", err)
@@ -59,72 +105,355 @@ func readWalletsFromCSV(filePath string) ([]WalletInfo, error) {
 		return nil, fmt.Errorf("CSV 文件为空或格式不正确")
 	}
 
-	// 验证表头
+	// 按列映射解析表头
 	headers := records[0]
-	expectedHeaders := []string{"Address", "Private Key", "Mnemonic"}
-	for i, header := range expectedHeaders {
-		if headers[i] != header {
-			return nil, fmt.Errorf("CSV 表头不正确，期望: %v, 实际: %v", expectedHeaders, headers)
-		}
+	cols, err := resolveWalletColumns(headers)
+	if err != nil {
+		return nil, fmt.Errorf("CSV 表头不正确: %v", err)
 	}
 
 	var wallets []WalletInfo
 	for i, record := range records[1:] {
-		if len(record) != 3 {
+		if len(record) != len(headers) {
 			return nil, fmt.Errorf("第 %d 行数据格式不正确", i+2)
 		}
-		wallets = append(wallets, WalletInfo{
-			Address:    strings.TrimSpace(record[0]),
-			PrivateKey: strings.TrimSpace(record[1]),
-			Mnemonic:   strings.TrimSpace(record[2]),
-		})
+		wallet := WalletInfo{
+			Address:    strings.TrimSpace(record[cols.Address]),
+			PrivateKey: strings.TrimSpace(record[cols.Key]),
+		}
+		if cols.Mnemonic >= 0 {
+			wallet.Mnemonic = strings.TrimSpace(record[cols.Mnemonic])
+		}
+		if cols.Amount >= 0 && strings.TrimSpace(record[cols.Amount]) != "" {
+			amount, err := parseAmount(strings.TrimSpace(record[cols.Amount]))
+			if err != nil {
+				return nil, fmt.Errorf("第 %d 行金额无效: %v", i+2, err)
+			}
+			wallet.Amount = amount
+		}
+		wallets = append(wallets, wallet)
 	}
 
 	return wallets, nil
 }
 
+// FailedBatch 记录执行失败的批次
+type FailedBatch struct {
+	Index  int // 批次序号（从 1 开始）
+	TxHash string
+	Reason string
+}
+
+// BatchSummary 批量转账的执行结果汇总
+type BatchSummary struct {
+	TotalWallets   int
+	TotalBatches   int
+	SuccessBatches int
+	FailedBatches  []FailedBatch
+	TxHashes       []string      // 所有已发送批次的交易哈希
+	SourceCounts   []SourceCount // 每个接收者文件读取到的接收者数量
+}
+
+// defaultBatchSize 是未指定 Config.BatchSize 时每批处理的钱包数量
+const defaultBatchSize = 300
+
 // 执行批量转账
-func ExecuteBatchTransfer(cfg *Config) error {
+func ExecuteBatchTransfer(cfg *Config) (*BatchSummary, error) {
+	summary := &BatchSummary{}
+
 	// 1. 读取接收者钱包信息
-	wallets, err := readWalletsFromCSV(cfg.CSVFilePath)
-	if err != nil {
-		return fmt.Errorf("读取接收者钱包信息失败: %v", err)
+	sourcePath := cfg.CSVFilePath
+	var wallets []Recipient
+	if cfg.RecipientsJSON != "" {
+		sourcePath = cfg.RecipientsJSON
+		recipients, err := readRecipientsFromJSON(cfg.RecipientsJSON)
+		if err != nil {
+			return summary, fmt.Errorf("读取接收者信息失败: %v", err)
+		}
+		wallets = recipients
+	} else {
+		paths := cfg.CSVFilePaths
+		if len(paths) == 0 {
+			paths = []string{cfg.CSVFilePath}
+		}
+		sourcePath = paths[0]
+		recipients, counts, duplicates, err := readRecipientsFromCSVs(paths, cfg.AmountPerWallet, cfg.Dedupe)
+		if err != nil {
+			return summary, fmt.Errorf("读取接收者钱包信息失败: %v", err)
+		}
+		wallets = recipients
+		summary.SourceCounts = counts
+		if len(paths) > 1 {
+			for _, count := range counts {
+				log.Printf("接收者文件 %s: %d 个接收者", count.Path, count.Count)
+			}
+		}
+		if cfg.Dedupe {
+			log.Printf("去重移除 %d 个重复地址，剩余 %d 个接收者", duplicates, len(wallets))
+		}
+	}
+
+	// 代币模式下接收者余额和已发送交易的调用数据都与原生币分账不同，依赖它们的选项无法使用
+	tokenMode := cfg.TokenAddress != ""
+	if tokenMode {
+		if !common.IsHexAddress(cfg.TokenAddress) {
+			return summary, fmt.Errorf("无效的代币地址: %s", cfg.TokenAddress)
+		}
+		if cfg.SkipFunded || cfg.TopUpTo != nil || cfg.VerifyAfter || cfg.VerifyLogs || cfg.ResumeFromTxHash != "" {
+			return summary, fmt.Errorf("代币模式不支持 --skip-funded、--top-up-to、--verify-after、--verify-logs 和 --resume-from-txhash")
+		}
 	}
 
 	totalWallets := len(wallets)
 	if cfg.MaxWallets > 0 && totalWallets > cfg.MaxWallets {
-		log.Printf("CSV 文件中包含 %d 个钱包，将只处理前 %d 个钱包", totalWallets, cfg.MaxWallets)
+		log.Printf("接收者列表中包含 %d 个钱包，将只处理前 %d 个钱包", totalWallets, cfg.MaxWallets)
 		wallets = wallets[:cfg.MaxWallets]
 		totalWallets = cfg.MaxWallets
 	}
+	if cfg.ExpectRecipients > 0 && totalWallets != cfg.ExpectRecipients {
+		return summary, fmt.Errorf("接收者数量校验失败: 期望 %d 个，实际加载 %d 个，请检查 CSV 文件是否被截断或选错 (--expect-recipients)", cfg.ExpectRecipients, totalWallets)
+	}
+	if err := checkRecipientSanity(totalWallets, sumRecipientAmounts(wallets), cfg.MaxTotalValue); err != nil {
+		return summary, err
+	}
 
-	batchSize := 300
-	totalBatches := (totalWallets + batchSize - 1) / batchSize
+	// 与上一次运行的报告比较，避免本意只新增少量接收者却重新发给所有人
+	if cfg.CompareTo != "" {
+		previous, err := readPreviousRun(cfg.CompareTo)
+		if err != nil {
+			return summary, err
+		}
+		logRecipientDelta(cfg.CompareTo, computeRecipientDelta(previous, wallets), cfg.Display)
+	}
+	if cfg.DryRun {
+		batchSize := cfg.BatchSize
+		if batchSize <= 0 {
+			batchSize = defaultBatchSize
+		}
+		summary.TotalWallets = totalWallets
+		summary.TotalBatches = (totalWallets + batchSize - 1) / batchSize
+		log.Printf("试运行: %d 个接收者，分 %d 批，总金额 %s，未发送任何交易",
+			totalWallets, summary.TotalBatches, cfg.Display.Format(sumRecipientAmounts(wallets)))
+		return summary, nil
+	}
 
-	log.Printf("总共处理 %d 个钱包地址，将分 %d 批处理，每批最多 %d 个地址", totalWallets, totalBatches, batchSize)
+	// 合约地址为空或无效时 HexToAddress 会得到零地址，调用没有代码的地址会成功并烧掉全部转账金额
+	if cfg.ContractAddress == "" {
+		return summary, fmt.Errorf("未指定批量转账合约地址")
+	}
+	if !common.IsHexAddress(cfg.ContractAddress) {
+		return summary, fmt.Errorf("无效的合约地址: %s", cfg.ContractAddress)
+	}
 
 	// 2. 连接以太坊网络
-	client, err := ethclient.Dial(cfg.RPCURL)
+	client, err := dialClient(context.Background(), cfg.RPCURL)
 	if err != nil {
-		return fmt.Errorf("连接以太坊网络失败: %v", err)
+		return summary, fmt.Errorf("连接以太坊网络失败: %v", err)
+	}
+
+	// 根据最后一笔已确认的批次交易恢复，跳过其覆盖的接收者及之前的所有接收者
+	if cfg.ResumeFromTxHash != "" {
+		resumeABI, err := loadBatchABI(cfg.ABIJSON, cfg.Method)
+		if err != nil {
+			return summary, err
+		}
+		resumeMethod := cfg.Method
+		if resumeMethod == "" {
+			resumeMethod = defaultBatchMethod
+		}
+		resumeIndex, err := resumeIndexFromTx(client, resumeABI, resumeMethod, common.HexToAddress(cfg.ContractAddress), cfg.ResumeFromTxHash, wallets)
+		if err != nil {
+			return summary, fmt.Errorf("恢复进度失败: %v", err)
+		}
+		log.Printf("交易 %s 已覆盖到第 %d 个接收者，将从第 %d 个接收者继续处理", cfg.ResumeFromTxHash, resumeIndex, resumeIndex+1)
+		wallets = wallets[resumeIndex:]
+		totalWallets = len(wallets)
+		if totalWallets == 0 {
+			log.Printf("所有接收者均已处理完毕，无需转账")
+			return summary, nil
+		}
 	}
 
+	// 跳过已有足够余额的接收者，使重复分账只补发尚未到账的钱包
+	if cfg.SkipFunded {
+		wallets, err = filterFundedRecipients(client, wallets)
+		if err != nil {
+			return summary, err
+		}
+		log.Printf("跳过 %d 个余额已达到转账金额的钱包", totalWallets-len(wallets))
+		totalWallets = len(wallets)
+		if totalWallets == 0 {
+			log.Printf("所有钱包余额均已达到转账金额，无需转账")
+			return summary, nil
+		}
+	}
+
+	// 补足模式：只发送达到目标余额所需的差额
+	if cfg.TopUpTo != nil {
+		wallets, err = topUpRecipients(client, wallets, cfg.TopUpTo)
+		if err != nil {
+			return summary, err
+		}
+		log.Printf("补足模式: 跳过 %d 个余额已达到 %s ETH 的钱包，需补足 %d 个钱包，补足总额 %s ETH",
+			totalWallets-len(wallets), formatEther(cfg.TopUpTo), len(wallets), formatEther(sumRecipientAmounts(wallets)))
+		totalWallets = len(wallets)
+		if totalWallets == 0 {
+			log.Printf("所有钱包余额均已达到目标余额，无需转账")
+			return summary, nil
+		}
+	}
+
+	batchSize := cfg.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultBatchSize
+	}
+	totalBatches := (totalWallets + batchSize - 1) / batchSize
+
+	// 读取并校验批次清单
+	var manifest map[int]BatchOverride
+	if cfg.ManifestPath != "" {
+		manifest, err = readBatchManifest(cfg.ManifestPath)
+		if err != nil {
+			return summary, err
+		}
+		if err := validateBatchManifest(manifest, totalBatches); err != nil {
+			return summary, err
+		}
+		log.Printf("已加载批次清单 %s，包含 %d 个批次覆盖设置", cfg.ManifestPath, len(manifest))
+	}
+
+	log.Printf("总共处理 %d 个钱包地址，将分 %d 批处理，每批最多 %d 个地址", totalWallets, totalBatches, batchSize)
+	summary.TotalWallets = totalWallets
+	summary.TotalBatches = totalBatches
+
 	// 3. 解析 ABI
-	parsedABI, err := abi.JSON(strings.NewReader(batchTransferABI))
+	loadABI, method := loadBatchABI, defaultBatchMethod
+	if tokenMode {
+		loadABI, method = loadBatchTokenABI, defaultBatchTokenMethod
+	}
+	parsedABI, err := loadABI(cfg.ABIJSON, cfg.Method)
 	if err != nil {
-		return fmt.Errorf("解析 ABI 失败: %v", err)
+		return summary, err
+	}
+	if cfg.Method != "" {
+		method = cfg.Method
 	}
 
 	// 4. 创建合约实例
 	contractAddress := common.HexToAddress(cfg.ContractAddress)
+	code, err := client.CodeAt(context.Background(), contractAddress, nil)
+	if err != nil {
+		return summary, fmt.Errorf("查询合约代码失败: %v", err)
+	}
+	if len(code) == 0 {
+		return summary, fmt.Errorf("合约地址 %s 上没有合约代码（可能连接了错误的网络），已中止以免转账金额丢失", contractAddress.Hex())
+	}
 	contract := bind.NewBoundContract(contractAddress, parsedABI, client, client, client)
 
 	// 5. 使用配置的发送者钱包创建交易选项
 	auth, err := getTransactOpts(client, cfg.SenderWallet.PrivateKey, cfg.GasPrice, cfg.GasLimit)
 	if err != nil {
-		return fmt.Errorf("创建交易选项失败: %v", err)
+		return summary, fmt.Errorf("创建交易选项失败: %v", err)
+	}
+
+	// EIP-1559 模式下使用费用上限代替 gas 价格
+	if cfg.FeeCaps != nil {
+		auth.GasPrice = nil
+		auth.GasFeeCap = cfg.FeeCaps.FeeCap
+		auth.GasTipCap = cfg.FeeCaps.TipCap
+	}
+
+	// 指定起始 nonce 时在本地递增，便于补发部分广播失败的交易
+	nonces := lib.NewNonceManager(client, auth.From)
+	if cfg.StartNonce != nil {
+		nonces.Set(*cfg.StartNonce)
+		log.Printf("使用指定的起始 nonce: %d", *cfg.StartNonce)
+	}
+
+	// 代币模式：金额按代币小数位数换算，合约通过 transferFrom 从发送者转出代币，已有授权额度足够时复用，不再发送 approve
+	var token common.Address
+	unit := cfg.Display
+	if tokenMode {
+		token = common.HexToAddress(cfg.TokenAddress)
+		decimals, err := readTokenDecimals(client, token)
+		if err != nil {
+			return summary, err
+		}
+		unit = DisplayUnit{Symbol: cfg.Display.Symbol, Decimals: decimals}
+		for i := range wallets {
+			if wallets[i].Amount, err = scaleToTokenUnits(wallets[i].Amount, decimals); err != nil {
+				return summary, err
+			}
+		}
+		for batch, override := range manifest {
+			if override.Amount != nil {
+				if override.Amount, err = scaleToTokenUnits(override.Amount, decimals); err != nil {
+					return summary, err
+				}
+				manifest[batch] = override
+			}
+		}
+		var approveNonces *lib.NonceManager
+		if cfg.StartNonce != nil {
+			approveNonces = nonces
+		}
+		required := tokenAmountRequired(wallets, batchSize, manifest)
+		if err := ensureTokenAllowance(client, auth, token, contractAddress, required, cfg.InfiniteApprove, approveNonces, unit, cfg.WaitTimeout); err != nil {
+			return summary, err
+		}
+	}
+
+	// 每个接收者的结果按批次写入报告文件
+	reportFormat := cfg.ReportFormat
+	if reportFormat == "" {
+		reportFormat = "csv"
+	}
+	reportPath := resultFilePath(sourcePath, "_batch_res", reportFormat)
+	recordBatch := func(recipients []common.Address, amounts []*big.Int, txHash string, gasUsed uint64, state, errMsg string) {
+		for i, recipient := range recipients {
+			result := TransferResult{
+				Address:   auth.From.Hex(),
+				Target:    recipient.Hex(),
+				Amount:    amounts[i].String(),
+				TxHash:    txHash,
+				GasUsed:   gasUsed,
+				IsSuccess: errMsg == "",
+				Error:     errMsg,
+				State:     state,
+			}
+			if err := appendResult(result, reportPath, reportFormat); err != nil {
+				log.Printf("写入结果文件失败: %v", err)
+				return
+			}
+		}
 	}
 
+	// 只输出失败模式下不输出每批的处理进度和成功日志
+	progressf := log.Printf
+	if cfg.OnlyFailures {
+		progressf = func(string, ...interface{}) {}
+	}
+
+	// 所有批次共享的重试预算
+	budget := newRetryBudget(cfg.MaxTotalRetries)
+
+	// 发送前记录接收者余额，全部批次完成后核对余额增加量
+	var balances *balanceCheck
+	if cfg.VerifyAfter {
+		addresses := make([]common.Address, len(wallets))
+		for i, wallet := range wallets {
+			addresses[i] = wallet.Address
+		}
+		balances, err = newBalanceCheck(client, addresses)
+		if err != nil {
+			return summary, err
+		}
+	}
+
+	// 访问列表模式下节点不支持时自动关闭，并统计节省的 gas
+	accessListSupported := true
+	var totalGasSaved uint64
+
 	// 6. 分批处理
 	for batchIndex := 0; batchIndex < totalBatches; batchIndex++ {
 		start := batchIndex * batchSize
@@ -134,72 +463,239 @@ func ExecuteBatchTransfer(cfg *Config) error {
 		}
 
 		currentBatch := wallets[start:end]
-		log.Printf("处理第 %d/%d 批，包含 %d 个地址", batchIndex+1, totalBatches, len(currentBatch))
+		override, hasOverride := manifest[batchIndex+1]
+		if hasOverride && override.Skip {
+			log.Printf("根据清单跳过第 %d/%d 批 (%d 个地址)", batchIndex+1, totalBatches, len(currentBatch))
+			continue
+		}
+		progressf("处理第 %d/%d 批，包含 %d 个地址", batchIndex+1, totalBatches, len(currentBatch))
+		if hasOverride && override.Amount != nil {
+			log.Printf("根据清单将第 %d 批每个地址的金额覆盖为 %s", batchIndex+1, unit.Format(override.Amount))
+			overridden := make([]Recipient, len(currentBatch))
+			for i, wallet := range currentBatch {
+				overridden[i] = Recipient{Address: wallet.Address, Amount: override.Amount}
+			}
+			currentBatch = overridden
+		}
 
 		// 准备当前批次的转账数据
 		var recipients []common.Address
 		var amounts []*big.Int
 		for _, wallet := range currentBatch {
-			recipients = append(recipients, common.HexToAddress(wallet.Address))
-			amounts = append(amounts, cfg.AmountPerWallet)
+			recipients = append(recipients, wallet.Address)
+			amounts = append(amounts, wallet.Amount)
 		}
 
-		// 计算当前批次的总金额
-		batchTotalAmount := new(big.Int).Mul(cfg.AmountPerWallet, big.NewInt(int64(len(currentBatch))))
+		// 计算当前批次的总金额，代币模式下交易不附带原生币
+		batchTotalAmount := sumRecipientAmounts(currentBatch)
+		callArgs := []interface{}{recipients, amounts}
+		if tokenMode {
+			batchTotalAmount = nil
+			callArgs = append([]interface{}{token}, callArgs...)
+		}
 		auth.Value = batchTotalAmount
 
+		// 准备调用数据
+		data, err := parsedABI.Pack(method, callArgs...)
+		if err != nil {
+			return summary, fmt.Errorf("第 %d 批打包调用数据失败: %v", batchIndex+1, err)
+		}
+		msg := ethereum.CallMsg{
+			From:  auth.From,
+			To:    &contractAddress,
+			Value: batchTotalAmount,
+			Data:  data,
+		}
+
 		// 如果没有设置固定的 gas limit，则进行估算
+		var plainGas uint64
 		if cfg.GasLimit == 0 {
-			// 准备调用数据
-			data, err := parsedABI.Pack("batchSend", recipients, amounts)
-			if err != nil {
-				return fmt.Errorf("第 %d 批打包调用数据失败: %v", batchIndex+1, err)
-			}
-
 			// 估算 gas
-			msg := ethereum.CallMsg{
-				From:  auth.From,
-				To:    &contractAddress,
-				Value: batchTotalAmount,
-				Data:  data,
-			}
-			gasLimit, err := client.EstimateGas(context.Background(), msg)
+			var gasLimit uint64
+			err := withRetries(context.Background(), fmt.Sprintf("第 %d 批估算 gas 限制", batchIndex+1), cfg.Retries, budget, func() error {
+				var err error
+				gasLimit, err = client.EstimateGas(context.Background(), msg)
+				return err
+			})
 			if err != nil {
-				return fmt.Errorf("第 %d 批估算 gas 限制失败: %v", batchIndex+1, err)
+				if !isRevertError(err) {
+					return summary, fmt.Errorf("第 %d 批估算 gas 限制失败: %v", batchIndex+1, err)
+				}
+				// 调用会回滚，重放调用以解析回滚原因（例如发送者余额不足、接收者地址无效）
+				reason := decodeRevertReason(client, msg, nil)
+				if cfg.OnEstimateFail != "skip" {
+					return summary, fmt.Errorf("第 %d 批估算 gas 限制失败，交易将会回滚: %s", batchIndex+1, reason)
+				}
+				log.Printf("第 %d 批估算 gas 限制失败，交易将会回滚: %s，跳过该批次", batchIndex+1, reason)
+				recordBatch(recipients, amounts, "", 0, "", "估算gas失败: "+reason)
+				summary.FailedBatches = append(summary.FailedBatches, FailedBatch{
+					Index:  batchIndex + 1,
+					Reason: "估算 gas 时回滚: " + reason,
+				})
+				continue
 			}
 
-			// 增加 20% 的 gas 限制作为缓冲
-			gasLimit = gasLimit * 12 / 10
+			plainGas = gasLimit
+			// 按 --gas-buffer 增加缓冲
+			gasLimit = applyGasBuffer(gasLimit, cfg.GasBuffer)
 			auth.GasLimit = gasLimit
 
-			log.Printf("第 %d 批估算 gas 限制: %d (包含 20%% 缓冲)", batchIndex+1, gasLimit)
+			progressf("第 %d 批估算 gas 限制: %d (包含 %d%% 缓冲)", batchIndex+1, gasLimit, cfg.GasBuffer)
 		} else {
-			log.Printf("第 %d 批使用固定 gas 限制: %d", batchIndex+1, cfg.GasLimit)
+			progressf("第 %d 批使用固定 gas 限制: %d", batchIndex+1, cfg.GasLimit)
+		}
+
+		// 访问列表模式：生成访问列表并重新估算，只有确实节省 gas 时才使用
+		var accessList types.AccessList
+		if cfg.AccessList && accessListSupported {
+			list, plain, withList, err := estimateWithAccessList(client, msg, plainGas)
+			switch {
+			case isMethodNotFound(err):
+				log.Printf("节点不支持 eth_createAccessList，后续批次不再使用访问列表")
+				accessListSupported = false
+			case err != nil:
+				log.Printf("第 %d 批生成访问列表失败，按普通交易发送: %v", batchIndex+1, err)
+			case withList >= plain:
+				log.Printf("第 %d 批使用访问列表不能节省 gas (%d >= %d)，按普通交易发送", batchIndex+1, withList, plain)
+			default:
+				accessList = list
+				totalGasSaved += plain - withList
+				if cfg.GasLimit == 0 {
+					auth.GasLimit = applyGasBuffer(withList, cfg.GasBuffer)
+				}
+				progressf("第 %d 批使用访问列表 (%d 个地址)，估算 gas 从 %d 降至 %d，节省 %d",
+					batchIndex+1, len(list), plain, withList, plain-withList)
+			}
+		}
+
+		// 手续费预算：按本批最高手续费判断是否还能发送
+		if cfg.GasBudget != nil {
+			gasPrice := cfg.GasPrice
+			if cfg.FeeCaps != nil {
+				gasPrice = cfg.FeeCaps.FeeCap
+			}
+			progressf("第 %d 批发送前剩余手续费预算: %s", batchIndex+1, formatEther(cfg.GasBudget.remaining()))
+			if err := cfg.GasBudget.check(auth.GasLimit, gasPrice); err != nil {
+				return summary, fmt.Errorf("第 %d 批未发送: %v", batchIndex+1, err)
+			}
 		}
 
 		// 发送交易
-		tx, err := contract.Transact(auth, "batchSend", recipients, amounts)
+		if cfg.StartNonce != nil {
+			nonce, err := nonces.Next(context.Background())
+			if err != nil {
+				return summary, fmt.Errorf("第 %d 批获取 nonce 失败: %v", batchIndex+1, err)
+			}
+			auth.Nonce = new(big.Int).SetUint64(nonce)
+		} else if cfg.Retries > 0 {
+			// 重试前固定 nonce，避免首次发送实际已被节点接收时重复转账
+			nonce, err := client.PendingNonceAt(context.Background(), auth.From)
+			if err != nil {
+				return summary, fmt.Errorf("第 %d 批获取 nonce 失败: %v", batchIndex+1, err)
+			}
+			auth.Nonce = new(big.Int).SetUint64(nonce)
+		}
+		// 先签名不发送，写入原始交易后再广播，发送失败的交易也能用 broadcast --raw-file 重新广播
+		var tx *types.Transaction
+		auth.NoSend = true
+		err = withRetries(context.Background(), fmt.Sprintf("第 %d 批签名交易", batchIndex+1), cfg.Retries, budget, func() error {
+			var err error
+			if accessList != nil && cfg.FeeCaps != nil {
+				// EIP-1559 交易直接携带访问列表
+				auth.AccessList = accessList
+				tx, err = contract.Transact(auth, method, callArgs...)
+				auth.AccessList = nil
+			} else if accessList != nil {
+				tx, err = signAccessListTx(client, auth, msg, accessList)
+			} else {
+				tx, err = contract.Transact(auth, method, callArgs...)
+			}
+			return err
+		})
+		auth.NoSend = false
+		if cfg.StartNonce == nil {
+			auth.Nonce = nil
+		}
+		if err != nil {
+			recordBatch(recipients, amounts, "", 0, "", "签名交易失败")
+			return summary, fmt.Errorf("第 %d 批签名交易失败: %v", batchIndex+1, err)
+		}
+		if cfg.DumpRawPath != "" {
+			if err := appendRawTransaction(cfg.DumpRawPath, tx); err != nil {
+				log.Printf("第 %d 批写入原始交易失败: %v", batchIndex+1, err)
+			}
+		}
+
+		// 发送交易，重试时发送同一笔已签名交易，不会重复转账
+		err = withRetries(context.Background(), fmt.Sprintf("第 %d 批发送交易", batchIndex+1), cfg.Retries, budget, func() error {
+			return client.SendTransaction(context.Background(), tx)
+		})
 		if err != nil {
-			return fmt.Errorf("第 %d 批发送交易失败: %v", batchIndex+1, err)
+			recordBatch(recipients, amounts, tx.Hash().Hex(), 0, "", "发送交易失败")
+			return summary, fmt.Errorf("第 %d 批发送交易失败，交易哈希: %s: %v", batchIndex+1, tx.Hash().Hex(), err)
 		}
 
-		log.Printf("第 %d 批交易已发送，交易哈希: %s", batchIndex+1, tx.Hash().Hex())
+		progressf("第 %d 批交易已发送，交易哈希: %s，nonce: %d", batchIndex+1, tx.Hash().Hex(), tx.Nonce())
+		summary.TxHashes = append(summary.TxHashes, tx.Hash().Hex())
 
 		// 等待交易确认
-		receipt, err := bind.WaitMined(context.Background(), client, tx)
-		if err != nil {
-			return fmt.Errorf("第 %d 批等待交易确认失败: %v", batchIndex+1, err)
+		receipt, state, err := waitMinedWithTimeout(client, tx, cfg.WaitTimeout)
+		if receipt == nil {
+			recordBatch(recipients, amounts, tx.Hash().Hex(), 0, state, "等待交易确认失败")
+			return summary, fmt.Errorf("第 %d 批等待交易确认失败 (状态: %s): %v", batchIndex+1, state, err)
 		}
 
-		if receipt.Status == 0 {
-			return fmt.Errorf("第 %d 批交易执行失败，交易哈希: %s", batchIndex+1, receipt.TxHash.Hex())
+		if cfg.GasBudget != nil {
+			cfg.GasBudget.record(receipt, tx)
+		}
+		if cfg.WebhookURL != "" {
+			var errMsg string
+			if receipt.Status == 0 {
+				errMsg = "交易执行失败"
+			}
+			payload := newBatchWebhookPayload(batchIndex+1, totalBatches, receipt.TxHash.Hex(), receipt.GasUsed, recipients, amounts, errMsg)
+			if err := postWebhook(cfg.WebhookURL, payload); err != nil {
+				log.Printf("第 %d 批回调 webhook 失败: %v", batchIndex+1, err)
+			}
 		}
 
-		log.Printf("第 %d 批转账成功！交易哈希: %s，实际使用 gas: %d",
-			batchIndex+1,
-			receipt.TxHash.Hex(),
-			receipt.GasUsed,
-		)
+		if receipt.Status == 0 {
+			msg.Gas = tx.Gas()
+			reason := decodeRevertReason(client, msg, receipt.BlockNumber)
+			recordBatch(recipients, amounts, receipt.TxHash.Hex(), receipt.GasUsed, state, "交易执行失败: "+reason)
+			if !cfg.ContinueOnRevert {
+				return summary, fmt.Errorf("第 %d 批交易执行失败，交易哈希: %s，回滚原因: %s", batchIndex+1, receipt.TxHash.Hex(), reason)
+			}
+			log.Printf("第 %d 批交易执行失败，交易哈希: %s，回滚原因: %s，继续处理后续批次", batchIndex+1, receipt.TxHash.Hex(), reason)
+			summary.FailedBatches = append(summary.FailedBatches, FailedBatch{
+				Index:  batchIndex + 1,
+				TxHash: receipt.TxHash.Hex(),
+				Reason: reason,
+			})
+		} else {
+			summary.SuccessBatches++
+			recordBatch(recipients, amounts, receipt.TxHash.Hex(), receipt.GasUsed, state, "")
+			if balances != nil {
+				for i, recipient := range recipients {
+					balances.add(recipient, amounts[i])
+				}
+			}
+			progressf("第 %d 批转账成功！交易哈希: %s，实际使用 gas: %d",
+				batchIndex+1,
+				receipt.TxHash.Hex(),
+				receipt.GasUsed,
+			)
+			if cfg.VerifyLogs {
+				if issues := verifyBatchLogs(parsedABI, contractAddress, receipt, recipients, amounts); len(issues) > 0 {
+					log.Printf("第 %d 批事件校验发现 %d 处差异:", batchIndex+1, len(issues))
+					for _, issue := range issues {
+						log.Printf("- %s", issue)
+					}
+				} else {
+					progressf("第 %d 批事件校验通过，%d 个接收者的金额均与事件一致", batchIndex+1, len(recipients))
+				}
+			}
+		}
 
 		// 如果不是最后一批，等待一段时间再处理下一批
 		if batchIndex < totalBatches-1 {
@@ -209,8 +705,61 @@ func ExecuteBatchTransfer(cfg *Config) error {
 		}
 	}
 
+	if cfg.AccessList && totalGasSaved > 0 {
+		log.Printf("访问列表共节省估算 gas: %d", totalGasSaved)
+	}
+	if balances != nil {
+		logBalanceVerification(balances, cfg.Display)
+	}
+	if len(summary.FailedBatches) > 0 {
+		log.Printf("所有批次处理完成！成功 %d 批，失败 %d 批:", summary.SuccessBatches, len(summary.FailedBatches))
+		for _, failed := range summary.FailedBatches {
+			log.Printf("- 第 %d 批，交易哈希: %s，回滚原因: %s", failed.Index, failed.TxHash, failed.Reason)
+		}
+		return summary, nil
+	}
 	log.Printf("所有批次处理完成！总共处理 %d 个钱包地址", totalWallets)
-	return nil
+	if len(summary.SourceCounts) > 1 {
+		for _, count := range summary.SourceCounts {
+			log.Printf("- %s: %d 个接收者", count.Path, count.Count)
+		}
+	}
+	return summary, nil
+}
+
+// filterFundedRecipients 过滤掉余额已达到各自转账金额的接收者
+func filterFundedRecipients(client *ethclient.Client, recipients []Recipient) ([]Recipient, error) {
+	var unfunded []Recipient
+	for _, recipient := range recipients {
+		balance, err := client.BalanceAt(context.Background(), recipient.Address, nil)
+		if err != nil {
+			return nil, fmt.Errorf("查询钱包 %s 余额失败: %v", recipient.Address.Hex(), err)
+		}
+		if balance.Cmp(recipient.Amount) >= 0 {
+			continue
+		}
+		unfunded = append(unfunded, recipient)
+	}
+	return unfunded, nil
+}
+
+// topUpRecipients 计算每个接收者补足到 target 所需的金额，跳过余额已达到 target 的接收者
+func topUpRecipients(client *ethclient.Client, recipients []Recipient, target *big.Int) ([]Recipient, error) {
+	var topUps []Recipient
+	for _, recipient := range recipients {
+		balance, err := client.BalanceAt(context.Background(), recipient.Address, nil)
+		if err != nil {
+			return nil, fmt.Errorf("查询钱包 %s 余额失败: %v", recipient.Address.Hex(), err)
+		}
+		if balance.Cmp(target) >= 0 {
+			continue
+		}
+		topUps = append(topUps, Recipient{
+			Address: recipient.Address,
+			Amount:  new(big.Int).Sub(target, balance),
+		})
+	}
+	return topUps, nil
 }
 
 // 辅助函数：创建交易选项
@@ -240,25 +789,85 @@ func getTransactOpts(client *ethclient.Client, privateKeyHex string, gasPrice *b
 var (
 	rpcURL             string
 	contractAddress    string
-	csvFilePath        string
+	csvFilePaths       []string
+	dedupe             bool
+	verifyLogs         bool          // 批次确认后核对合约事件
+	waitTimeout        time.Duration // 等待每批交易确认的超时时间
+	useAccessList      bool          // 为批次交易附加访问列表
+	compareTo          string        // 上一次运行的报告文件
+	dryRun             bool          // 只输出计划，不发送交易
+	verifyAfter        bool          // 完成后核对接收者余额增加量
+	recipientsJSONPath string
 	senderCSVPath      string // 新增：发送者钱包 CSV 文件路径
 	senderIndex        int    // 新增：发送者钱包在 CSV 中的索引
-	amountPerWallet    float64
+	amountPerWallet    string
+	amountUSD          string  // 以美元计的每个钱包转账金额
+	maxTotalValue      string  // 总转账金额上限
+	maxGasTotal        string  // 手续费总额上限
+	webhookURL         string  // 每批确认后回调的地址
+	gasBuffer          int     // 估算 gas 后增加的缓冲百分比
+	priceURL           string  // 原生币美元价格接口
+	pricePath          string  // 价格在接口 JSON 中的字段路径
+	priceFallback      float64 // 价格接口不可用时的备用价格 (USD)
 	gasPriceMultiplier float64
+	fixedGasPriceGwei  float64 // 固定 gas 价格 (Gwei)，大于 0 时替代倍率
+	gasOracleURL       string  // 外部 gas 价格接口
+	maxFeeGwei         float64 // EIP-1559 最高费用 (Gwei)
+	batchRetries       int     // 每批临时错误的最大重试次数
+	onlyFailures       bool    // 只输出失败的批次和最终汇总
+	maxTotalRetries    int     // 整个运行共享的重试次数上限
+	maxPriorityGwei    float64 // EIP-1559 优先费 (Gwei)
+	gasOraclePath      string  // gas 价格在接口 JSON 中的字段路径
 	batchSize          int
 	fixedGasLimit      uint64
 	maxWallets         int
+	expectRecipients   int
+	skipFunded         bool
+	tokenAddress       string // 分发的 ERC-20 代币地址
+	infiniteApprove    bool   // 代币授权不足时授权最大值
+	topUpTo            string
+	startNonce         int64
+	manifestPath       string
+	reportFormat       string
+	continueOnRevert   bool
+	abiJSON            string // 内联的分账合约 ABI JSON
+	abiFile            string // 分账合约 ABI 文件路径
+	batchMethod        string // 批量转账方法名
+	onEstimateFail     string // 估算 gas 时调用回滚的处理方式
+	dumpRawPath        string // 已签名交易十六进制的输出文件
+	resumeFromTxHash   string // 从该批次交易之后继续处理
+	displaySymbol      string // 日志中金额的单位符号
+	displayDecimals    int    // 日志中金额的小数位数
+	autoRPC            bool
+	autoRPCNodesFile   string // --auto-rpc 探测的节点文件
+	expectChainID      int64
 )
 
 // BatchTransferCmd 是批量转账命令
 var BatchTransferCmd = &cobra.Command{
-	Use:   "batch-transfer",
-	Short: "执行批量转账操作",
-	Long:  `从 CSV 文件中读取钱包地址，并执行批量转账操作。支持分批处理和动态 gas 价格。`,
+	Use:     "batch-transfer",
+	Aliases: []string{"bt"},
+	Short:   "执行批量转账操作",
+	Long:    `从 CSV 文件中读取钱包地址，并执行批量转账操作。支持分批处理和动态 gas 价格。`,
 	Run: func(cmd *cobra.Command, args []string) {
 		// 验证必需参数
-		if csvFilePath == "" {
-			log.Fatal("请提供接收者钱包 CSV 文件路径 (--csv)")
+		if len(csvFilePaths) == 0 && recipientsJSONPath == "" {
+			log.Fatal("请提供接收者钱包 CSV 文件路径 (--csv) 或接收者 JSON 文件路径 (--recipients-json)")
+		}
+		stdinCount := 0
+		for _, path := range csvFilePaths {
+			if path == stdinPath {
+				stdinCount++
+			}
+		}
+		if stdinCount > 1 {
+			log.Fatal("标准输入只能读取一次，--csv - 只能指定一次")
+		}
+		if stdinCount > 0 && senderCSVPath == stdinPath {
+			log.Fatal("接收者和发送者钱包不能同时从标准输入读取")
+		}
+		if len(csvFilePaths) > 0 && recipientsJSONPath != "" {
+			log.Fatal("--csv 和 --recipients-json 不能同时使用")
 		}
 		if senderCSVPath == "" {
 			log.Fatal("请提供发送者钱包 CSV 文件路径 (--sender-csv)")
@@ -269,9 +878,146 @@ var BatchTransferCmd = &cobra.Command{
 		if batchSize <= 0 {
 			log.Fatal("批次大小必须大于 0 (--batch-size)")
 		}
+		if gasBuffer < 0 {
+			log.Fatal("gas 缓冲百分比不能为负数 (--gas-buffer)")
+		}
+		if batchSize > maxSaneBatchSize {
+			log.Fatalf("批次大小 %d 超过上限 %d，单笔交易几乎一定超过区块 gas 上限 (--batch-size)", batchSize, maxSaneBatchSize)
+		}
+		maxTotalValueWei, err := parseMaxTotalValue(maxTotalValue)
+		if err != nil {
+			log.Fatal(err)
+		}
+		feeBudget, err := parseMaxGasTotal(maxGasTotal)
+		if err != nil {
+			log.Fatal(err)
+		}
 		if maxWallets < 0 {
 			log.Fatal("最大钱包数量不能为负数 (--max-wallets)")
 		}
+		// 代币模式：默认的分账合约只支持原生币，必须显式指定实现 batchSendToken 的合约
+		if tokenAddress != "" {
+			if !common.IsHexAddress(tokenAddress) {
+				log.Fatalf("无效的代币地址 (--token): %s", tokenAddress)
+			}
+			if !cmd.Flags().Changed("contract") || contractAddress == "" {
+				log.Fatal("使用 --token 时必须通过 --contract 指定支持代币分发的分账合约")
+			}
+			if amountUSD != "" {
+				log.Fatal("--amount-usd 按原生币价格换算，不能与 --token 同时使用")
+			}
+			if !cmd.Flags().Changed("method") {
+				batchMethod = defaultBatchTokenMethod
+			}
+			if !cmd.Flags().Changed("symbol") {
+				displaySymbol = "代币"
+			}
+		} else if infiniteApprove {
+			log.Fatal("--infinite-approve 只能与 --token 同时使用")
+		}
+		if expectRecipients < 0 {
+			log.Fatal("期望接收者数量不能为负数 (--expect-recipients)")
+		}
+		if batchRetries < 0 || maxTotalRetries < 0 {
+			log.Fatal("重试次数不能为负数 (--retries, --max-total-retries)")
+		}
+		if err := validateReportFormat(reportFormat); err != nil {
+			log.Fatal(err)
+		}
+		if displayDecimals < 0 {
+			log.Fatal("小数位数不能为负数 (--decimals)")
+		}
+		if onEstimateFail != "abort" && onEstimateFail != "skip" {
+			log.Fatalf("不支持的估算失败处理方式: %s，可选值: abort, skip (--on-estimate-fail)", onEstimateFail)
+		}
+		if abiJSON != "" && abiFile != "" {
+			log.Fatal("--abi-json 和 --abi-file 不能同时使用")
+		}
+		if abiFile != "" {
+			content, err := readABIFile(abiFile)
+			if err != nil {
+				log.Fatal(err)
+			}
+			abiJSON = content
+		}
+		// 提前校验 ABI，避免连接网络后才发现方法不匹配
+		loadABI := loadBatchABI
+		if tokenAddress != "" {
+			loadABI = loadBatchTokenABI
+		}
+		parsedABI, err := loadABI(abiJSON, batchMethod)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if verifyLogs && len(parsedABI.Events) == 0 {
+			log.Fatal("ABI 中没有事件定义，无法使用 --verify-logs（请通过 --abi-json 或 --abi-file 提供包含事件的 ABI）")
+		}
+		amountWei, err := parseAmount(amountPerWallet)
+		if err != nil {
+			log.Fatalf("转账金额无效 (--amount): %v", err)
+		}
+		if amountWei.Sign() <= 0 {
+			log.Fatal("转账金额必须大于 0 (--amount)")
+		}
+		// 按美元金额和实时价格换算每个钱包的转账金额
+		if amountUSD != "" {
+			if cmd.Flags().Changed("amount") {
+				log.Fatal("--amount 和 --amount-usd 不能同时使用")
+			}
+			amountWei, err = resolveUSDAmount(amountUSD, priceURL, pricePath, priceFallback)
+			if err != nil {
+				log.Fatal(err)
+			}
+		}
+
+		if fixedGasPriceGwei < 0 {
+			log.Fatal("gas 价格不能为负数 (--gas-price)")
+		}
+		if fixedGasPriceGwei > 0 && cmd.Flags().Changed("gas-multiplier") {
+			log.Fatal("--gas-price 和 --gas-multiplier 不能同时使用")
+		}
+		if fixedGasPriceGwei > 0 && (maxFeeGwei > 0 || maxPriorityGwei > 0) {
+			log.Fatal("--gas-price 不能与 --max-fee-gwei / --max-priority-gwei 同时使用")
+		}
+
+		var startNonceValue *uint64
+		if startNonce >= 0 {
+			value := uint64(startNonce)
+			startNonceValue = &value
+		}
+
+		var topUpToWei *big.Int
+		if topUpTo != "" {
+			amount, err := parseAmount(topUpTo)
+			if err != nil {
+				log.Fatalf("目标余额无效 (--top-up-to): %v", err)
+			}
+			if amount.Sign() <= 0 {
+				log.Fatal("目标余额必须大于 0 (--top-up-to)")
+			}
+			topUpToWei = amount
+		}
+
+		// 试运行只需要接收者列表，不读取发送者钱包也不连接节点
+		if dryRun {
+			cfg := &Config{
+				CSVFilePaths:     csvFilePaths,
+				Dedupe:           dedupe,
+				RecipientsJSON:   recipientsJSONPath,
+				AmountPerWallet:  amountWei,
+				MaxWallets:       maxWallets,
+				ExpectRecipients: expectRecipients,
+				BatchSize:        batchSize,
+				CompareTo:        compareTo,
+				DryRun:           true,
+				MaxTotalValue:    maxTotalValueWei,
+				Display:          DisplayUnit{Symbol: displaySymbol, Decimals: displayDecimals},
+			}
+			if _, err := ExecuteBatchTransfer(cfg); err != nil {
+				log.Fatalf("试运行失败: %v", err)
+			}
+			return
+		}
 
 		// 读取发送者钱包信息
 		senderWallets, err := readWalletsFromCSV(senderCSVPath)
@@ -283,14 +1029,33 @@ var BatchTransferCmd = &cobra.Command{
 		}
 		senderWallet := senderWallets[senderIndex]
 
+		// 未指定 --rpc 时自动选择最快的节点
+		if autoRPCNodesFile != "" && !autoRPC {
+			log.Fatal("--rpc-nodes-file 需要与 --auto-rpc 一起使用")
+		}
+		if autoRPC && !cmd.Flags().Changed("rpc") {
+			rpcURL, err = autoSelectRPC(autoRPCNodesFile, expectChainID)
+			if err != nil {
+				log.Fatalf("自动选择 RPC 节点失败: %v", err)
+			}
+		}
+
 		// 连接以太坊网络
-		client, err := ethclient.Dial(rpcURL)
+		client, err := dialClient(context.Background(), rpcURL)
 		if err != nil {
 			log.Fatalf("连接以太坊网络失败: %v", err)
 		}
 
+		// 未指定 --contract 时按所连接网络的链 ID 选择默认合约
+		if contractAddress == "" {
+			contractAddress, err = defaultSplitterContract(client)
+			if err != nil {
+				log.Fatal(err)
+			}
+		}
+
 		// 获取当前网络的平均 gas 价格
-		suggestedGasPrice, err := client.SuggestGasPrice(context.Background())
+		suggestedGasPrice, err := baseGasPrice(client, gasOracleURL, gasOraclePath)
 		if err != nil {
 			log.Fatalf("获取网络 gas 价格失败: %v", err)
 		}
@@ -301,32 +1066,87 @@ var BatchTransferCmd = &cobra.Command{
 			big.NewInt(int64(gasPriceMultiplier*100)),
 		)
 		gasPriceWei = gasPriceWei.Div(gasPriceWei, big.NewInt(100))
-
-		// 转换金额为 Wei
-		amountWei := new(big.Int).Mul(
-			big.NewInt(int64(amountPerWallet*1e18)),
-			big.NewInt(1),
-		)
+		// 指定固定 gas 价格时直接使用，不再参考网络建议价格
+		if fixedGasPriceGwei > 0 {
+			gasPriceWei = gweiToWei(fixedGasPriceGwei)
+		}
+		caps, err := resolveFeeCaps(client, maxFeeGwei, maxPriorityGwei)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if caps != nil {
+			// 余额和手续费按最高费用估算
+			gasPriceWei = caps.FeeCap
+		}
 
 		cfg := &Config{
-			RPCURL:          rpcURL,
-			ContractAddress: contractAddress,
-			CSVFilePath:     csvFilePath,
-			AmountPerWallet: amountWei,
-			GasLimit:        fixedGasLimit,
-			GasPrice:        gasPriceWei,
-			MaxWallets:      maxWallets,
-			SenderWallet:    senderWallet, // 新增：设置发送者钱包
+			RPCURL:           rpcURL,
+			ContractAddress:  contractAddress,
+			CSVFilePaths:     csvFilePaths,
+			Dedupe:           dedupe,
+			VerifyLogs:       verifyLogs,
+			WaitTimeout:      waitTimeout,
+			Retries:          batchRetries,
+			OnlyFailures:     onlyFailures,
+			MaxTotalRetries:  maxTotalRetries,
+			AccessList:       useAccessList,
+			CompareTo:        compareTo,
+			DryRun:           dryRun,
+			VerifyAfter:      verifyAfter,
+			MaxTotalValue:    maxTotalValueWei,
+			GasBudget:        feeBudget,
+			WebhookURL:       webhookURL,
+			GasBuffer:        gasBuffer,
+			RecipientsJSON:   recipientsJSONPath,
+			AmountPerWallet:  amountWei,
+			GasLimit:         fixedGasLimit,
+			GasPrice:         gasPriceWei,
+			FeeCaps:          caps,
+			MaxWallets:       maxWallets,
+			ExpectRecipients: expectRecipients,
+			BatchSize:        batchSize,
+			ManifestPath:     manifestPath,
+			SenderWallet:     senderWallet, // 新增：设置发送者钱包
+			SkipFunded:       skipFunded,
+			TokenAddress:     tokenAddress,
+			InfiniteApprove:  infiniteApprove,
+			TopUpTo:          topUpToWei,
+			StartNonce:       startNonceValue,
+			ReportFormat:     reportFormat,
+			ContinueOnRevert: continueOnRevert,
+			ABIJSON:          abiJSON,
+			Method:           batchMethod,
+			OnEstimateFail:   onEstimateFail,
+			DumpRawPath:      dumpRawPath,
+			ResumeFromTxHash: resumeFromTxHash,
+			Display:          DisplayUnit{Symbol: displaySymbol, Decimals: displayDecimals},
 		}
 
 		log.Printf("配置信息:")
 		log.Printf("- RPC URL: %s", cfg.RPCURL)
 		log.Printf("- 合约地址: %s", cfg.ContractAddress)
 		log.Printf("- 发送者钱包: %s (索引: %d)", cfg.SenderWallet.Address, senderIndex)
-		log.Printf("- 接收者钱包 CSV: %s", cfg.CSVFilePath)
-		log.Printf("- 每个钱包转账金额: %.4f ETH", float64(cfg.AmountPerWallet.Int64())/1e18)
+		if cfg.TokenAddress != "" {
+			log.Printf("- 分发代币: %s", cfg.TokenAddress)
+		}
+		if cfg.TopUpTo != nil {
+			log.Printf("- 补足模式: 将每个钱包余额补足到 %s ETH", formatEther(cfg.TopUpTo))
+		}
+		if cfg.RecipientsJSON != "" {
+			log.Printf("- 接收者 JSON: %s", cfg.RecipientsJSON)
+			log.Printf("- 每个钱包转账金额: 使用 JSON 中的金额")
+		} else {
+			log.Printf("- 接收者钱包 CSV: %s", strings.Join(cfg.CSVFilePaths, ", "))
+			log.Printf("- 每个钱包转账金额: %s", cfg.Display.Format(cfg.AmountPerWallet))
+		}
 		log.Printf("- 网络建议 Gas 价格: %.1f Gwei", float64(suggestedGasPrice.Int64())/1e9)
-		log.Printf("- 实际使用 Gas 价格: %.1f Gwei (%.1f 倍)", float64(cfg.GasPrice.Int64())/1e9, gasPriceMultiplier)
+		if cfg.FeeCaps != nil {
+			log.Printf("- EIP-1559 费用上限: %s", cfg.FeeCaps)
+		} else if fixedGasPriceGwei > 0 {
+			log.Printf("- 实际使用 Gas 价格: %.4f Gwei (固定价格 --gas-price)", float64(cfg.GasPrice.Int64())/1e9)
+		} else {
+			log.Printf("- 实际使用 Gas 价格: %.1f Gwei (%.1f 倍)", float64(cfg.GasPrice.Int64())/1e9, gasPriceMultiplier)
+		}
 		if cfg.GasLimit > 0 {
 			log.Printf("- 使用固定 Gas 限制: %d", cfg.GasLimit)
 		} else {
@@ -339,7 +1159,7 @@ var BatchTransferCmd = &cobra.Command{
 			log.Printf("- 最大处理钱包数量: 不限制")
 		}
 
-		if err := ExecuteBatchTransfer(cfg); err != nil {
+		if _, err := ExecuteBatchTransfer(cfg); err != nil {
 			log.Fatalf("批量转账失败: %v", err)
 		}
 	},
@@ -347,18 +1167,59 @@ var BatchTransferCmd = &cobra.Command{
 
 func init() {
 	BatchTransferCmd.Flags().StringVar(&rpcURL, "rpc", "https://bsc-dataseed.binance.org/", "以太坊 RPC URL")
-	BatchTransferCmd.Flags().StringVar(&contractAddress, "contract", "0x61e0336Ba3bEd95deD28b01ef9cD015d7F32437d", "批量转账合约地址")
-	BatchTransferCmd.Flags().StringVar(&csvFilePath, "csv", "", "接收者钱包 CSV 文件路径")
+	BatchTransferCmd.Flags().StringVar(&contractAddress, "contract", "", "批量转账合约地址（为空时按链 ID 使用已知的默认合约）")
+	BatchTransferCmd.Flags().StringArrayVar(&csvFilePaths, "csv", nil, "接收者钱包 CSV 文件路径，可重复指定多个文件依次合并，- 表示从标准输入读取")
+	BatchTransferCmd.Flags().BoolVar(&verifyAfter, "verify-after", false, "全部批次完成后重新查询接收者余额，核对余额增加量（相对发送前余额）不少于已确认发送的金额，输出未收到预期金额的接收者")
+	BatchTransferCmd.Flags().BoolVar(&dryRun, "dry-run", false, "只读取接收者并输出批次计划和总金额，不连接节点也不发送交易（不应用 --skip-funded、--top-up-to 和 --resume-from-txhash）")
+	BatchTransferCmd.Flags().StringVar(&compareTo, "compare-to", "", "上一次运行的 JSON/JSONL 报告（--report-format json 或 jsonl 生成），发送前输出新增、移除和金额变化的接收者")
+	BatchTransferCmd.Flags().BoolVar(&useAccessList, "access-list", false, "调用 eth_createAccessList 为批次交易生成访问列表 (EIP-2930)，重新估算 gas 并在节省时以访问列表交易发送；节点不支持时自动跳过")
+	BatchTransferCmd.Flags().BoolVar(&onlyFailures, "only-failures", false, "只输出失败的批次和最终汇总，不输出每批的处理进度和成功日志（配合 --continue-on-revert 使用）")
+	BatchTransferCmd.Flags().IntVar(&batchRetries, "retries", 0, "每批估算 gas 或发送交易遇到临时 RPC 错误（超时、限流、连接断开等）时的最大重试次数")
+	BatchTransferCmd.Flags().IntVar(&maxTotalRetries, "max-total-retries", 0, "整个运行中所有批次重试次数的总上限，用完后中止运行 (0 表示不限制)")
+	BatchTransferCmd.Flags().DurationVar(&waitTimeout, "wait-timeout", 0, "等待每批交易确认的超时时间（例如 5m），超时后将交易标记为 pending/replaced/dropped 写入报告 (0 表示一直等待)")
+	BatchTransferCmd.Flags().BoolVar(&verifyLogs, "verify-logs", false, "批次确认后按 ABI 解码合约事件，核对接收者数量和金额（需要合约发出转账事件）")
+	BatchTransferCmd.Flags().BoolVar(&dedupe, "dedupe", false, "合并多个接收者 CSV 时按地址去重（保留首次出现的记录）")
+	BatchTransferCmd.Flags().StringVar(&recipientsJSONPath, "recipients-json", "", "接收者 JSON 文件路径，格式为 [{\"address\": \"0x...\", \"amount\": \"0.01\"}]，金额以 ETH 为单位")
 	BatchTransferCmd.Flags().StringVar(&senderCSVPath, "sender-csv", "wallets/senders/w1.csv", "发送者钱包 CSV 文件路径")
 	BatchTransferCmd.Flags().IntVar(&senderIndex, "sender-index", 0, "发送者钱包在 CSV 中的索引")
-	BatchTransferCmd.Flags().Float64Var(&amountPerWallet, "amount", 0.1, "每个钱包转账金额 (ETH)")
+	BatchTransferCmd.Flags().IntVar(&gasBuffer, "gas-buffer", defaultGasBuffer, "估算 gas 后增加的缓冲百分比，0 表示原样使用估算值（gas 消耗随链上状态变化的合约缓冲过小可能 out of gas）")
+	BatchTransferCmd.Flags().StringVar(&webhookURL, "webhook-url", "", "每批交易确认后向该地址 POST JSON（批次序号、交易哈希、gas 用量、接收者、总金额、是否成功），回调失败只记录日志不中止运行")
+	BatchTransferCmd.Flags().StringVar(&maxGasTotal, "max-gas-total", "", "整个运行可花费的手续费总额（按回执 gasUsed × gas 价格累计，支持 wei/gwei/ether 单位后缀），下一批的最高手续费会超出剩余预算时中止（为空表示不限制）")
+	BatchTransferCmd.Flags().StringVar(&maxTotalValue, "max-total-value", "", "总转账金额上限，超过时在发送任何交易前拒绝运行，用于发现金额多打 0 等错误（为空表示不限制）")
+	BatchTransferCmd.Flags().StringVar(&amountUSD, "amount-usd", "", "以美元计的每个钱包转账金额，运行时按 --price-url 返回的原生币价格换算，不能与 --amount 同时使用")
+	BatchTransferCmd.Flags().StringVar(&priceURL, "price-url", "", "返回原生币美元价格的 HTTP JSON 接口")
+	BatchTransferCmd.Flags().StringVar(&pricePath, "price-path", ".price", "美元价格在接口 JSON 中的字段路径，例如 .price 或 .binancecoin.usd")
+	BatchTransferCmd.Flags().Float64Var(&priceFallback, "price-fallback", 0, "价格接口不可用时使用的备用美元价格 (0 表示获取失败时中止)")
+	BatchTransferCmd.Flags().StringVar(&amountPerWallet, "amount", "0.1", "每个钱包转账金额 (ETH)，支持 1,000.5、1e-3、0.000_1 及 wei/gwei/ether 单位后缀")
+	BatchTransferCmd.Flags().Float64Var(&maxFeeGwei, "max-fee-gwei", 0, "EIP-1559 最高费用 maxFeePerGas (Gwei)，设置后发送 EIP-1559 交易，未设置时取 2 倍基础费用加优先费")
+	BatchTransferCmd.Flags().Float64Var(&maxPriorityGwei, "max-priority-gwei", 0, "EIP-1559 优先费 maxPriorityFeePerGas (Gwei)，设置后发送 EIP-1559 交易，未设置时取节点建议值，不能高于最高费用")
+	BatchTransferCmd.Flags().StringVar(&gasOracleURL, "gas-oracle-url", "", "外部 gas 价格接口 (HTTP JSON)，设置后替代节点建议价格作为倍率的基础，接口不可用时回退到节点")
+	BatchTransferCmd.Flags().StringVar(&gasOraclePath, "gas-oracle-path", ".fast", "gas 价格在接口 JSON 中的字段路径（以点分隔，值以 Gwei 为单位），例如 .fast 或 result.FastGasPrice")
 	BatchTransferCmd.Flags().Float64Var(&gasPriceMultiplier, "gas-multiplier", 1.0001, "Gas 价格倍率 (相对于网络平均 gas 价格)")
-	BatchTransferCmd.Flags().IntVar(&batchSize, "batch-size", 300, "每批处理的钱包数量")
+	BatchTransferCmd.Flags().Float64Var(&fixedGasPriceGwei, "gas-price", 0, "固定的 Gas 价格 (Gwei)，大于 0 时替代网络建议价格和倍率，不能与 --gas-multiplier 同时使用")
+	BatchTransferCmd.Flags().IntVar(&batchSize, "batch-size", defaultBatchSize, "每批处理的钱包数量")
 	BatchTransferCmd.Flags().Uint64Var(&fixedGasLimit, "gas-limit", 0, "固定的 Gas 限制 (如果设置，将跳过估算)")
 	BatchTransferCmd.Flags().IntVar(&maxWallets, "max-wallets", 0, "最大处理钱包数量 (0 表示不限制)")
-
-	// 只标记 csv 参数为必需
-	BatchTransferCmd.MarkFlagRequired("csv")
+	BatchTransferCmd.Flags().IntVar(&expectRecipients, "expect-recipients", 0, "断言加载的接收者数量（应用 --max-wallets 后）等于该值，否则在发送前中止 (0 表示不校验)")
+	BatchTransferCmd.Flags().StringVar(&displaySymbol, "symbol", "ETH", "日志中转账金额的单位符号（代币分发时设置为代币符号）")
+	BatchTransferCmd.Flags().IntVar(&displayDecimals, "decimals", 18, "日志中转账金额的小数位数（例如 USDT 为 6）")
+	BatchTransferCmd.Flags().StringVar(&resumeFromTxHash, "resume-from-txhash", "", "最后一笔已确认批次的交易哈希，解码其接收者后从下一个未覆盖的接收者继续处理")
+	BatchTransferCmd.Flags().StringVar(&dumpRawPath, "dump-raw", "", "签名后立即将每笔交易的原始数据（十六进制）追加写入该文件（包括发送失败的交易和提高 gas 价格后重签的交易），可用 broadcast --raw-file 重新广播")
+	BatchTransferCmd.Flags().StringVar(&onEstimateFail, "on-estimate-fail", "abort", "估算 gas 时调用回滚的处理方式：abort 中止，skip 记录失败并跳过该批次")
+	BatchTransferCmd.Flags().StringVar(&abiJSON, "abi-json", "", "内联的分账合约 ABI JSON（替代内置 ABI，与 --abi-file 二选一）")
+	BatchTransferCmd.Flags().StringVar(&abiFile, "abi-file", "", "分账合约 ABI JSON 文件路径")
+	BatchTransferCmd.Flags().StringVar(&batchMethod, "method", defaultBatchMethod, "批量转账方法名，参数必须为 (address[], uint256[])；使用 --token 时默认 batchSendToken，参数必须为 (address, address[], uint256[])")
+	BatchTransferCmd.Flags().StringVar(&reportFormat, "report-format", "csv", "转账报告格式 (csv, json, jsonl)")
+	BatchTransferCmd.Flags().BoolVar(&continueOnRevert, "continue-on-revert", false, "批次交易回滚时记录失败并继续处理后续批次 (默认中止)")
+	BatchTransferCmd.Flags().BoolVar(&autoRPC, "auto-rpc", false, "未指定 --rpc 时自动探测并使用响应最快的节点")
+	BatchTransferCmd.Flags().StringVar(&autoRPCNodesFile, "rpc-nodes-file", "", "--auto-rpc 探测的节点文件，格式与 check-rpc --nodes-file 相同，标注了链 ID 的节点链 ID 不一致时不会被选中（为空时探测内置 BSC 节点）")
+	BatchTransferCmd.Flags().Int64Var(&expectChainID, "expect-chain-id", 0, "自动选择节点时要求的链 ID (0 表示不校验)")
+	BatchTransferCmd.Flags().BoolVar(&skipFunded, "skip-funded", false, "跳过余额已达到转账金额的接收者钱包")
+	BatchTransferCmd.Flags().StringVar(&tokenAddress, "token", "", "分发的 ERC-20 代币地址，设置后调用分账合约的 batchSendToken 从发送者转出代币（需要 --contract 指定支持代币的合约；--amount 按代币单位填写，按代币的 decimals() 换算）")
+	BatchTransferCmd.Flags().BoolVar(&infiniteApprove, "infinite-approve", false, "代币授权额度不足时授权 uint256 最大值而不是本次所需总额，之后的运行无需再次 approve（授权额度足够时总是跳过 approve）")
+	BatchTransferCmd.Flags().StringVar(&manifestPath, "manifest", "", "批次清单 JSON 文件，可按批次序号（从 1 开始）覆盖金额或跳过批次，金额优先级高于 Amount 列和 --amount")
+	BatchTransferCmd.Flags().Int64Var(&startNonce, "start-nonce", -1, "起始 nonce (-1 表示自动获取)，之后每批在本地递增")
+	BatchTransferCmd.Flags().StringVar(&topUpTo, "top-up-to", "", "补足模式：将每个接收者余额补足到该值 (ETH)，只发送差额，已达到的跳过；金额格式同 --amount")
 }
 
 // RunBatchTransfer 是批量转账命令的入口点
@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"AccountSplitting/lib"
+	"fmt"
+	"log"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	benchCount       int
+	benchDuration    time.Duration
+	benchConcurrency int
+	benchMode        string
+)
+
+// benchGenerate 用 workers 个协程反复调用 gen，生成 count 个（count 为 0 时持续 duration）后返回生成数量和耗时
+func benchGenerate(gen func() error, count int, duration time.Duration, workers int) (int, time.Duration, error) {
+	var generated int64
+	var firstErr error
+	var once sync.Once
+	var wg sync.WaitGroup
+	start := time.Now()
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				if count > 0 {
+					if atomic.AddInt64(&generated, 1) > int64(count) {
+						return
+					}
+				} else if time.Since(start) >= duration {
+					return
+				}
+				if err := gen(); err != nil {
+					once.Do(func() { firstErr = err })
+					return
+				}
+				if count == 0 {
+					atomic.AddInt64(&generated, 1)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	elapsed := time.Since(start)
+	total := int(atomic.LoadInt64(&generated))
+	if count > 0 && total > count {
+		total = count
+	}
+	return total, elapsed, firstErr
+}
+
+// BenchCmd 是测试钱包生成速度的命令
+var BenchCmd = &cobra.Command{
+	Use:   "bench-gen",
+	Short: "测试钱包生成速度（钱包/秒），用于估算大批量生成的耗时",
+	Long:  `在内存中生成钱包（不写入任何文件），分别测试普通钱包（GWallets）和助记词钱包（GMnemonicW）在单协程和 --concurrency 个协程下的生成速度，输出每秒生成数量、并发加速比和生成一百万个钱包的预计耗时。可按 --count 固定数量或按 --duration 固定时长测试。`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if benchCount < 0 {
+			log.Fatal("生成数量不能为负数 (--count)")
+		}
+		if benchCount == 0 && benchDuration <= 0 {
+			log.Fatal("请指定生成数量 (--count) 或测试时长 (--duration)")
+		}
+		if benchConcurrency <= 0 {
+			log.Fatal("并发数必须大于 0 (--concurrency)")
+		}
+
+		generators := map[string]func() error{
+			"plain": func() error {
+				_, err := lib.GWallets(1)
+				return err
+			},
+			"mnemonic": func() error {
+				_, _, _, err := lib.GMnemonicW()
+				return err
+			},
+		}
+		var modes []string
+		switch benchMode {
+		case "all":
+			modes = []string{"plain", "mnemonic"}
+		case "plain", "mnemonic":
+			modes = []string{benchMode}
+		default:
+			log.Fatalf("不支持的测试模式: %s (可选 plain, mnemonic, all)", benchMode)
+		}
+		workerCounts := []int{1}
+		if benchConcurrency > 1 {
+			workerCounts = append(workerCounts, benchConcurrency)
+		}
+
+		if benchCount > 0 {
+			log.Printf("每项测试生成 %d 个钱包，CPU 核数: %d", benchCount, runtime.NumCPU())
+		} else {
+			log.Printf("每项测试持续 %v，CPU 核数: %d", benchDuration, runtime.NumCPU())
+		}
+		fmt.Printf("%-10s %-8s %-10s %-12s %-10s %s\n", "类型", "协程数", "生成数量", "钱包/秒", "加速比", "一百万个预计耗时")
+		for _, mode := range modes {
+			var baseline float64
+			for _, workers := range workerCounts {
+				total, elapsed, err := benchGenerate(generators[mode], benchCount, benchDuration, workers)
+				if err != nil {
+					log.Fatalf("%s 钱包生成失败: %v", mode, err)
+				}
+				rate := float64(total) / elapsed.Seconds()
+				if workers == 1 {
+					baseline = rate
+				}
+				eta := time.Duration(float64(time.Second) * 1e6 / rate).Round(time.Second)
+				fmt.Printf("%-10s %-8d %-10d %-12.1f %-10s %v\n", mode, workers, total, rate, fmt.Sprintf("%.2fx", rate/baseline), eta)
+			}
+		}
+	},
+}
+
+func init() {
+	BenchCmd.Flags().IntVarP(&benchCount, "count", "n", 0, "每项测试生成的钱包数量（0 表示按 --duration 计时）")
+	BenchCmd.Flags().DurationVar(&benchDuration, "duration", 5*time.Second, "每项测试的持续时间（--count 为 0 时使用）")
+	BenchCmd.Flags().IntVar(&benchConcurrency, "concurrency", runtime.NumCPU(), "并发测试使用的协程数（1 表示只测试单协程）")
+	BenchCmd.Flags().StringVar(&benchMode, "mode", "all", "测试的钱包类型: plain（普通钱包）、mnemonic（助记词钱包）、all")
+}
@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/spf13/cobra"
+)
+
+var (
+	broadcastRPCURL  string
+	broadcastRawFile string
+	broadcastRawTxs  []string
+)
+
+// readRawTransactions 读取每行一笔的十六进制原始交易
+func readRawTransactions(filePath string) ([]*types.Transaction, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("打开交易文件失败: %v", err)
+	}
+	defer file.Close()
+
+	var txs []*types.Transaction
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		tx, err := decodeRawTransaction(line)
+		if err != nil {
+			return nil, fmt.Errorf("第 %d 行: %v", lineNumber, err)
+		}
+		txs = append(txs, tx)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("读取交易文件失败: %v", err)
+	}
+	return txs, nil
+}
+
+// decodeRawTransaction 解码十六进制原始交易
+func decodeRawTransaction(rawHex string) (*types.Transaction, error) {
+	rawHex = strings.TrimSpace(rawHex)
+	if !strings.HasPrefix(rawHex, "0x") && !strings.HasPrefix(rawHex, "0X") {
+		rawHex = "0x" + rawHex
+	}
+	raw, err := hexutil.Decode(rawHex)
+	if err != nil {
+		return nil, fmt.Errorf("原始交易不是有效的十六进制: %v", err)
+	}
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(raw); err != nil {
+		return nil, fmt.Errorf("解码原始交易失败: %v", err)
+	}
+	return tx, nil
+}
+
+// BroadcastCmd 是广播预签名交易的命令
+var BroadcastCmd = &cobra.Command{
+	Use:   "broadcast",
+	Short: "广播预签名的原始交易并等待确认",
+	Long:  `通过 --raw-tx 直接指定十六进制原始交易，或读取 prepare-txs 生成的原始交易文件（每行一笔），依次发送到网络并等待交易确认，最后汇总结果。也可用于手动重新广播卡住的交易。`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if broadcastRawFile == "" && len(broadcastRawTxs) == 0 {
+			log.Fatal("请提供原始交易 (--raw-tx) 或原始交易文件路径 (--raw-file)")
+		}
+
+		var txs []*types.Transaction
+		for i, rawHex := range broadcastRawTxs {
+			tx, err := decodeRawTransaction(rawHex)
+			if err != nil {
+				log.Fatalf("第 %d 个 --raw-tx 无效: %v", i+1, err)
+			}
+			txs = append(txs, tx)
+		}
+		if broadcastRawFile != "" {
+			fileTxs, err := readRawTransactions(broadcastRawFile)
+			if err != nil {
+				log.Fatalf("读取原始交易失败: %v", err)
+			}
+			txs = append(txs, fileTxs...)
+		}
+		if len(txs) == 0 {
+			log.Fatal("原始交易文件中没有交易")
+		}
+
+		client, err := dialClient(context.Background(), broadcastRPCURL)
+		if err != nil {
+			log.Fatalf("连接以太坊网络失败: %v", err)
+		}
+
+		// 先全部发送，再逐个等待确认
+		var sent []*types.Transaction
+		failCount := 0
+		for i, tx := range txs {
+			if err := client.SendTransaction(context.Background(), tx); err != nil {
+				log.Printf("第 %d 笔交易发送失败 (%s): %v", i+1, tx.Hash().Hex(), err)
+				failCount++
+				continue
+			}
+			log.Printf("第 %d/%d 笔交易已发送，交易哈希: %s", i+1, len(txs), tx.Hash().Hex())
+			sent = append(sent, tx)
+		}
+
+		successCount := 0
+		for _, tx := range sent {
+			receipt, err := bind.WaitMined(context.Background(), client, tx)
+			if err != nil {
+				log.Printf("等待交易确认失败 (%s): %v", tx.Hash().Hex(), err)
+				failCount++
+				continue
+			}
+			if receipt.Status == 0 {
+				log.Printf("交易执行失败，交易哈希: %s", receipt.TxHash.Hex())
+				failCount++
+				continue
+			}
+			log.Printf("交易成功！交易哈希: %s，实际使用 gas: %d", receipt.TxHash.Hex(), receipt.GasUsed)
+			successCount++
+		}
+
+		log.Printf("\n广播完成！成功: %d，失败: %d", successCount, failCount)
+	},
+}
+
+func init() {
+	BroadcastCmd.Flags().StringVar(&broadcastRPCURL, "rpc", "https://bsc-dataseed.binance.org/", "以太坊 RPC URL")
+	BroadcastCmd.Flags().StringArrayVar(&broadcastRawTxs, "raw-tx", nil, "十六进制原始交易，可重复指定")
+	BroadcastCmd.Flags().StringVar(&broadcastRawFile, "raw-file", "", "原始交易文件路径（每行一笔十六进制交易）")
+}
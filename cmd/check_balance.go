@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+)
+
+var (
+	checkBalanceRPCURL      string
+	checkBalanceCSVPath     string
+	checkBalanceMulticall   string
+	checkBalanceBatchSize   int
+	checkBalanceConcurrency int
+	checkBalanceOutput      string
+	checkBalanceSort        string
+)
+
+// balanceRow 是一个地址的查询结果，Index 为其在输入 CSV 中的位置
+type balanceRow struct {
+	Index   int
+	Address common.Address
+	Balance *big.Int
+}
+
+// sortBalanceRows 按 balance（从高到低）、address 或 input（输入顺序）排序，相同时保持输入顺序
+func sortBalanceRows(rows []balanceRow, by string) error {
+	switch by {
+	case "input":
+	case "balance":
+		sort.SliceStable(rows, func(i, j int) bool { return rows[i].Balance.Cmp(rows[j].Balance) > 0 })
+	case "address":
+		sort.SliceStable(rows, func(i, j int) bool {
+			return strings.ToLower(rows[i].Address.Hex()) < strings.ToLower(rows[j].Address.Hex())
+		})
+	default:
+		return fmt.Errorf("不支持的排序方式: %s (可选 input, balance, address)", by)
+	}
+	return nil
+}
+
+// CheckBalanceCmd 是批量查询钱包原生币余额的命令
+var CheckBalanceCmd = &cobra.Command{
+	Use:   "check-balance",
+	Short: "批量查询 CSV 中所有钱包的原生币余额",
+	Long:  `读取 CSV 中的地址并查询原生币余额。指定 --multicall（Multicall3 合约地址）时，每次 RPC 请求通过 getEthBalance 批量查询 --multicall-batch 个地址，大量钱包时比逐个查询快得多；未指定时并发逐个调用 eth_getBalance。`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if checkBalanceCSVPath == "" {
+			log.Fatal("请提供钱包 CSV 文件路径 (--csv)")
+		}
+		if checkBalanceMulticall != "" && !common.IsHexAddress(checkBalanceMulticall) {
+			log.Fatalf("无效的 Multicall3 合约地址 (--multicall): %s", checkBalanceMulticall)
+		}
+		if checkBalanceBatchSize <= 0 {
+			log.Fatal("每次批量查询的地址数量必须大于 0 (--multicall-batch)")
+		}
+		if checkBalanceConcurrency <= 0 {
+			log.Fatal("并发数必须大于 0 (--concurrency)")
+		}
+		if err := sortBalanceRows(nil, checkBalanceSort); err != nil {
+			log.Fatal(err)
+		}
+
+		addresses, err := readAddressesFromCSV(checkBalanceCSVPath)
+		if err != nil {
+			log.Fatalf("读取钱包 CSV 文件失败: %v", err)
+		}
+
+		client, err := dialClient(context.Background(), checkBalanceRPCURL)
+		if err != nil {
+			log.Fatalf("连接以太坊网络失败: %v", err)
+		}
+
+		start := time.Now()
+		var balances []*big.Int
+		if checkBalanceMulticall != "" {
+			log.Printf("通过 Multicall3 %s 查询 %d 个地址的余额（每批 %d 个）...", checkBalanceMulticall, len(addresses), checkBalanceBatchSize)
+			balances, err = fetchBalancesMulticall(client, common.HexToAddress(checkBalanceMulticall), addresses, checkBalanceBatchSize)
+		} else {
+			log.Printf("逐个查询 %d 个地址的余额（并发数 %d）...", len(addresses), checkBalanceConcurrency)
+			balances, err = fetchBalancesIndividually(client, addresses, checkBalanceConcurrency)
+		}
+		if err != nil {
+			log.Fatalf("查询余额失败: %v", err)
+		}
+		elapsed := time.Since(start).Round(time.Millisecond)
+
+		// 并发查询的结果已按输入位置收集，排序后输出保证多次运行结果可直接比较
+		rows := make([]balanceRow, len(addresses))
+		for i, addr := range addresses {
+			rows[i] = balanceRow{Index: i, Address: addr, Balance: balances[i]}
+		}
+		sortBalanceRows(rows, checkBalanceSort)
+
+		var writer *csv.Writer
+		if checkBalanceOutput != "" {
+			if err := os.MkdirAll(filepath.Dir(checkBalanceOutput), 0755); err != nil {
+				log.Fatalf("创建输出目录失败: %v", err)
+			}
+			file, err := os.Create(checkBalanceOutput)
+			if err != nil {
+				log.Fatalf("创建输出文件失败: %v", err)
+			}
+			defer file.Close()
+			writer = csv.NewWriter(file)
+			writer.Write([]string{"index", "address", "balance_wei", "balance"})
+		}
+
+		total := new(big.Int)
+		empty := 0
+		for _, row := range rows {
+			log.Printf("%s: %s", row.Address.Hex(), formatEther(row.Balance))
+			total.Add(total, row.Balance)
+			if row.Balance.Sign() == 0 {
+				empty++
+			}
+			if writer != nil {
+				writer.Write([]string{strconv.Itoa(row.Index), row.Address.Hex(), row.Balance.String(), formatEther(row.Balance)})
+			}
+		}
+		if writer != nil {
+			writer.Flush()
+			if err := writer.Error(); err != nil {
+				log.Fatalf("写入输出文件失败: %v", err)
+			}
+			log.Printf("余额已写入 %s", checkBalanceOutput)
+		}
+
+		log.Printf("\n查询完成！地址 %d 个，余额为 0 的 %d 个，余额合计 %s，耗时 %v", len(addresses), empty, formatEther(total), elapsed)
+	},
+}
+
+func init() {
+	CheckBalanceCmd.Flags().StringVar(&checkBalanceRPCURL, "rpc", "https://bsc-dataseed.binance.org/", "以太坊 RPC URL")
+	CheckBalanceCmd.Flags().StringVar(&checkBalanceCSVPath, "csv", "", "钱包 CSV 文件路径（只需要地址列）")
+	CheckBalanceCmd.Flags().StringVar(&checkBalanceMulticall, "multicall", "", "Multicall3 合约地址，指定后批量查询余额（大多数链为 0xcA11bde05977b3631167028862bE2a173976CA11）")
+	CheckBalanceCmd.Flags().IntVar(&checkBalanceBatchSize, "multicall-batch", 500, "使用 Multicall3 时每次 RPC 请求查询的地址数量")
+	CheckBalanceCmd.Flags().IntVar(&checkBalanceConcurrency, "concurrency", 10, "未使用 Multicall3 时查询余额的最大并发请求数")
+	CheckBalanceCmd.Flags().StringVar(&checkBalanceSort, "sort", "input", "输出顺序: input（CSV 中的顺序）、balance（余额从高到低）、address（地址字典序）")
+	CheckBalanceCmd.Flags().StringVarP(&checkBalanceOutput, "output", "o", "", "余额输出 CSV 文件路径（为空时只输出日志）")
+
+	CheckBalanceCmd.MarkFlagRequired("csv")
+}
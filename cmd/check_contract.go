@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log"
+	"math/big"
+	"os"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/spf13/cobra"
+)
+
+var (
+	checkContractRPCURL  string
+	checkContractAddress string
+	checkContractABIFile string
+	checkContractMethod  string
+	checkContractFrom    string
+)
+
+// checkContractRecipient 是试调用时使用的占位接收者
+var checkContractRecipient = common.HexToAddress("0x000000000000000000000000000000000000dEaD")
+
+// callOutcome 是一次 eth_call 的结果：成功，或回滚（附带是否返回了回滚数据和解码后的原因）
+type callOutcome struct {
+	OK       bool
+	Reverted bool
+	HasData  bool
+	Reason   string
+}
+
+// tryCall 执行 eth_call，把回滚与网络错误区分开，网络错误直接返回
+func tryCall(client *ethclient.Client, msg ethereum.CallMsg) (callOutcome, error) {
+	_, err := client.CallContract(context.Background(), msg, nil)
+	if err == nil {
+		return callOutcome{OK: true}, nil
+	}
+	if !isRevertError(err) {
+		return callOutcome{}, err
+	}
+	outcome := callOutcome{Reverted: true, Reason: revertReasonFromError(err)}
+	var dataErr rpc.DataError
+	if errors.As(err, &dataErr) {
+		hexData, _ := dataErr.ErrorData().(string)
+		outcome.HasData = len(hexData) > len("0x")
+	}
+	return outcome, nil
+}
+
+// CheckContractCmd 是检查分账合约是否支持批量转账方法的命令
+var CheckContractCmd = &cobra.Command{
+	Use:   "check-contract",
+	Short: "检查分账合约地址是否有代码，以及是否支持 --method 的调用格式",
+	Long:  `在正式转账前检查 --contract：确认地址上有合约代码，检查合约代码中是否包含方法选择器，并用一个占位接收者、金额 0 对方法做一次 eth_call，再用一个不存在的选择器做对照调用，以区分“方法存在但调用回滚”和“方法不存在”。不兼容时以非 0 状态码退出。未指定 --contract 时使用所连接链的默认合约。`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if checkContractAddress != "" && !common.IsHexAddress(checkContractAddress) {
+			log.Fatalf("无效的合约地址 (--contract): %s", checkContractAddress)
+		}
+		if checkContractFrom != "" && !common.IsHexAddress(checkContractFrom) {
+			log.Fatalf("无效的调用者地址 (--from): %s", checkContractFrom)
+		}
+		var abiJSON string
+		if checkContractABIFile != "" {
+			data, err := readABIFile(checkContractABIFile)
+			if err != nil {
+				log.Fatal(err)
+			}
+			abiJSON = data
+		}
+		parsedABI, err := loadBatchABI(abiJSON, checkContractMethod)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		client, err := dialClient(context.Background(), checkContractRPCURL)
+		if err != nil {
+			log.Fatalf("连接以太坊网络失败: %v", err)
+		}
+		if checkContractAddress == "" {
+			checkContractAddress, err = defaultSplitterContract(client)
+			if err != nil {
+				log.Fatal(err)
+			}
+		}
+		contract := common.HexToAddress(checkContractAddress)
+		log.Printf("检查合约 %s 的方法 %s", contract.Hex(), parsedABI.Methods[checkContractMethod].Sig)
+
+		// 1. 地址上必须有合约代码
+		code, err := client.CodeAt(context.Background(), contract, nil)
+		if err != nil {
+			log.Fatalf("查询合约代码失败: %v", err)
+		}
+		if len(code) == 0 {
+			log.Printf("✗ 地址 %s 上没有合约代码（可能是普通账户或连接了错误的网络）", contract.Hex())
+			os.Exit(1)
+		}
+		log.Printf("✓ 合约代码 %d 字节", len(code))
+
+		// 2. Solidity 合约的分发逻辑以 PUSH4 <选择器> 的形式包含每个外部方法的选择器
+		selector := parsedABI.Methods[checkContractMethod].ID
+		hasSelector := bytes.Contains(code, append([]byte{0x63}, selector...))
+		if hasSelector {
+			log.Printf("✓ 合约代码中包含方法选择器 0x%x", selector)
+		} else {
+			log.Printf("? 合约代码中未找到方法选择器 0x%x（代理合约的方法在实现合约中，此项仅供参考）", selector)
+		}
+
+		// 3. 用占位接收者、金额 0 试调用，并用不存在的选择器对照
+		data, err := parsedABI.Pack(checkContractMethod, []common.Address{checkContractRecipient}, []*big.Int{big.NewInt(0)})
+		if err != nil {
+			log.Fatalf("打包调用数据失败: %v", err)
+		}
+		msg := ethereum.CallMsg{From: common.HexToAddress(checkContractFrom), To: &contract, Value: big.NewInt(0), Data: data}
+		outcome, err := tryCall(client, msg)
+		if err != nil {
+			log.Fatalf("试调用失败: %v", err)
+		}
+		bogus := msg
+		bogus.Data = append([]byte{0xde, 0xad, 0xbe, 0xef}, data[4:]...)
+		bogusOutcome, err := tryCall(client, bogus)
+		if err != nil {
+			log.Fatalf("对照调用失败: %v", err)
+		}
+
+		compatible := false
+		switch {
+		case outcome.OK && bogusOutcome.OK:
+			log.Printf("? 试调用成功，但合约接受任意方法调用（有回退函数），无法仅凭调用确认方法存在")
+			compatible = hasSelector
+		case outcome.OK:
+			log.Printf("✓ 试调用成功，合约接受 %s 的调用格式", checkContractMethod)
+			compatible = true
+		case outcome.HasData:
+			log.Printf("✓ 方法存在，试调用回滚: %s（占位参数回滚属正常情况）", outcome.Reason)
+			compatible = true
+		case bogusOutcome.Reverted && !bogusOutcome.HasData:
+			log.Printf("? 试调用与不存在方法的对照调用都无原因回滚，方法可能不存在")
+			compatible = hasSelector
+		default:
+			log.Printf("? 试调用无原因回滚: %s", outcome.Reason)
+			compatible = hasSelector
+		}
+
+		if !compatible {
+			log.Printf("\n结论: 合约 %s 看起来不支持 %s，请检查 --contract、--method 和 --abi-file", contract.Hex(), checkContractMethod)
+			os.Exit(1)
+		}
+		log.Printf("\n结论: 合约 %s 看起来兼容 %s", contract.Hex(), parsedABI.Methods[checkContractMethod].Sig)
+	},
+}
+
+func init() {
+	CheckContractCmd.Flags().StringVar(&checkContractRPCURL, "rpc", "https://bsc-dataseed.binance.org/", "以太坊 RPC URL")
+	CheckContractCmd.Flags().StringVar(&checkContractAddress, "contract", "", "分账合约地址（为空时按链 ID 使用已知的默认合约）")
+	CheckContractCmd.Flags().StringVar(&checkContractABIFile, "abi-file", "", "分账合约 ABI JSON 文件路径（为空时使用内置的 batchSend ABI）")
+	CheckContractCmd.Flags().StringVar(&checkContractMethod, "method", defaultBatchMethod, "批量转账方法名，参数须为 (address[], uint256[])")
+	CheckContractCmd.Flags().StringVar(&checkContractFrom, "from", "", "试调用使用的调用者地址（合约限制调用者时指定，为空时使用零地址）")
+}
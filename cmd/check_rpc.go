@@ -12,7 +12,6 @@ import (
 	"sync"
 	"time"
 
-	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/spf13/cobra"
 )
 
@@ -21,80 +20,126 @@ type NodeResult struct {
 	URL          string
 	ResponseTime time.Duration
 	BlockHeight  *big.Int
+	ChainID      *big.Int
 	Error        error
 }
 
+// BSC 节点列表
+var defaultBSCNodes = []string{
+	"https://bsc-dataseed.binance.org/",
+	"https://bsc-dataseed1.defibit.io/",
+	"https://bsc-dataseed1.ninicoin.io/",
+	"https://bsc-dataseed2.defibit.io/",
+	"https://bsc-dataseed3.defibit.io/",
+	"https://bsc-dataseed4.defibit.io/",
+	"https://bsc-dataseed2.ninicoin.io/",
+	"https://bsc-dataseed3.ninicoin.io/",
+	"https://bsc-dataseed4.ninicoin.io/",
+	"https://bsc-dataseed1.binance.org/",
+	"https://bsc-dataseed2.binance.org/",
+	"https://bsc-dataseed3.binance.org/",
+	"https://bsc-dataseed4.binance.org/",
+}
+
 var (
 	rpcTimeout   int
 	showStats    bool
 	outputFormat string
+	maxLatency   int
+	verifyBlock  bool
+	blockNumber  uint64
+	appendCSV    string // 每次运行追加带时间戳的检查结果，用于积累延迟历史
+	nodesFile    string // 节点文件，每行一个 URL，可选第二列为预期链 ID
 )
 
 // CheckRPCCmd 是检查 RPC 节点的命令
 var CheckRPCCmd = &cobra.Command{
-	Use:   "check-rpc",
-	Short: "检查 BSC RPC 节点的可用性和响应时间",
-	Long:  `检查多个 BSC RPC 节点的可用性、响应时间和区块高度。`,
+	Use:     "check-rpc",
+	Aliases: []string{"rpc"},
+	Short:   "检查 BSC RPC 节点的可用性和响应时间",
+	Long:    `检查多个 BSC RPC 节点的可用性、响应时间和区块高度。使用 --nodes-file 检查自定义节点，文件中可为每个节点标注预期链 ID，链 ID 不一致的节点视为失败，结果按链分组输出，可一次检查多条链的节点。`,
 	Run: func(cmd *cobra.Command, args []string) {
-		// BSC 节点列表
-		nodes := []string{
-			"https://bsc-dataseed.binance.org/",
-			"https://bsc-dataseed1.defibit.io/",
-			"https://bsc-dataseed1.ninicoin.io/",
-			"https://bsc-dataseed2.defibit.io/",
-			"https://bsc-dataseed3.defibit.io/",
-			"https://bsc-dataseed4.defibit.io/",
-			"https://bsc-dataseed2.ninicoin.io/",
-			"https://bsc-dataseed3.ninicoin.io/",
-			"https://bsc-dataseed4.ninicoin.io/",
-			"https://bsc-dataseed1.binance.org/",
-			"https://bsc-dataseed2.binance.org/",
-			"https://bsc-dataseed3.binance.org/",
-			"https://bsc-dataseed4.binance.org/",
+		nodes := defaultRPCNodes()
+		if nodesFile != "" {
+			var err error
+			nodes, err = readNodesFile(nodesFile)
+			if err != nil {
+				log.Fatal(err)
+			}
 		}
-
-		// 创建结果通道
-		results := make(chan NodeResult, len(nodes))
-		var wg sync.WaitGroup
-
-		// 为每个节点启动检查协程
-		for _, node := range nodes {
-			wg.Add(1)
-			go func(nodeURL string) {
-				defer wg.Done()
-				ctx, cancel := context.WithTimeout(context.Background(), time.Duration(rpcTimeout)*time.Second)
-				defer cancel()
-				checkNode(ctx, nodeURL, results)
-			}(node)
+		allResults := probeAllNodes(nodeURLs(nodes), time.Duration(rpcTimeout)*time.Second)
+		checkExpectedChains(allResults, nodes)
+		for _, result := range allResults {
+			if result.Error != nil {
+				log.Printf("节点 %s 检查失败: %v", result.URL, result.Error)
+			}
 		}
-
-		// 等待所有检查完成
-		go func() {
-			wg.Wait()
-			close(results)
-		}()
-
-		// 收集结果
-		var nodeResults []NodeResult
-		for result := range results {
-			if result.Error == nil {
-				nodeResults = append(nodeResults, result)
+		if appendCSV != "" {
+			if err := appendNodeHistory(appendCSV, time.Now(), allResults); err != nil {
+				log.Fatalf("追加检查历史失败: %v", err)
 			}
 		}
+		nodeResults := healthyNodes(allResults)
 
-		// 按响应时间排序
-		sort.Slice(nodeResults, func(i, j int) bool {
-			return nodeResults[i].ResponseTime < nodeResults[j].ResponseTime
-		})
+		// 按响应时间阈值过滤
+		if maxLatency > 0 {
+			threshold := time.Duration(maxLatency) * time.Millisecond
+			var fastResults []NodeResult
+			for _, result := range nodeResults {
+				if result.ResponseTime < threshold {
+					fastResults = append(fastResults, result)
+				}
+			}
+			log.Printf("已过滤 %d 个响应时间超过 %d ms 的节点", len(nodeResults)-len(fastResults), maxLatency)
+			nodeResults = fastResults
+		}
 
-		// 输出结果
+		// 输出结果，多条链的节点按链分组
+		groups := groupByChain(nodeResults)
 		switch outputFormat {
 		case "json":
 			outputJSON(nodeResults)
 		case "csv":
-			outputCSV(nodeResults)
+			var sorted []NodeResult
+			for _, group := range groups {
+				sorted = append(sorted, group...)
+			}
+			outputCSV(sorted)
 		default:
-			outputText(nodeResults, showStats)
+			if len(groups) > 1 {
+				for _, group := range groups {
+					outputText(fmt.Sprintf("链 ID %s 节点检查结果", group[0].ChainID), group, showStats)
+				}
+			} else {
+				outputText("BSC 节点检查结果", nodeResults, showStats)
+			}
+		}
+
+		// 比较同一条链的各节点在同一高度的区块哈希，找出可能分叉或被篡改的节点
+		if verifyBlock && len(nodeResults) > 0 {
+			for _, group := range groups {
+				if len(groups) > 1 {
+					log.Printf("校验链 ID %s 的 %d 个节点:", group[0].ChainID, len(group))
+				}
+				height := blockNumber
+				if height == 0 {
+					// 未指定高度时使用所有节点都已同步到的较新区块
+					lowest := group[0].BlockHeight.Uint64()
+					for _, result := range group {
+						lowest = min(lowest, result.BlockHeight.Uint64())
+					}
+					if lowest > 5 {
+						height = lowest - 5
+					}
+				}
+				var urls []string
+				for _, result := range group {
+					urls = append(urls, result.URL)
+				}
+				if _, err := reportBlockVerification(urls, height, time.Duration(rpcTimeout)*time.Second); err != nil {
+					log.Fatalf("区块哈希校验失败: %v", err)
+				}
+			}
 		}
 	},
 }
@@ -103,12 +148,110 @@ func init() {
 	CheckRPCCmd.Flags().IntVar(&rpcTimeout, "timeout", 5, "RPC 请求超时时间（秒）")
 	CheckRPCCmd.Flags().BoolVar(&showStats, "stats", false, "显示统计信息")
 	CheckRPCCmd.Flags().StringVar(&outputFormat, "format", "text", "输出格式 (text, json, csv)")
+	CheckRPCCmd.Flags().BoolVar(&verifyBlock, "verify-block", false, "比较各节点在同一高度的区块哈希，标记与多数不一致的节点")
+	CheckRPCCmd.Flags().Uint64Var(&blockNumber, "block-number", 0, "用于校验的区块高度 (0 表示使用所有节点都已同步的较新区块)")
+	CheckRPCCmd.Flags().StringVar(&appendCSV, "append-csv", "", "将本次每个节点的检查结果（含时间戳和失败节点）追加到该 CSV，定时运行可积累延迟历史")
+	CheckRPCCmd.Flags().StringVar(&nodesFile, "nodes-file", "", "节点文件，每行一个 RPC URL，可选第二列为该节点预期的链 ID（例如 https://rpc.ankr.com/eth,1），链 ID 不一致的节点视为失败，结果按链分组输出（为空时检查内置 BSC 节点）")
+	CheckRPCCmd.Flags().IntVar(&maxLatency, "max-latency", 0, "只保留响应时间低于该值的节点（毫秒，0 表示不过滤）")
+}
+
+// probeAllNodes 并发检查所有节点，返回包括失败节点在内的全部结果
+func probeAllNodes(nodes []string, timeout time.Duration) []NodeResult {
+	// 创建结果通道
+	results := make(chan NodeResult, len(nodes))
+	var wg sync.WaitGroup
+
+	// 为每个节点启动检查协程
+	for _, node := range nodes {
+		wg.Add(1)
+		go func(nodeURL string) {
+			defer wg.Done()
+			// 单个节点异常不影响其他节点的检查
+			defer func() {
+				if r := recover(); r != nil {
+					log.Printf("检查节点 %s 时发生 panic: %v", nodeURL, r)
+					results <- NodeResult{URL: nodeURL, Error: fmt.Errorf("检查节点时发生 panic: %v", r)}
+				}
+			}()
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+			checkNode(ctx, nodeURL, results)
+		}(node)
+	}
+
+	// 等待所有检查完成
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	// 收集结果
+	var nodeResults []NodeResult
+	for result := range results {
+		nodeResults = append(nodeResults, result)
+	}
+	return nodeResults
+}
+
+// healthyNodes 过滤掉检查失败的节点，并按响应时间排序
+func healthyNodes(results []NodeResult) []NodeResult {
+	var nodeResults []NodeResult
+	for _, result := range results {
+		if result.Error == nil {
+			nodeResults = append(nodeResults, result)
+		}
+	}
+
+	// 按响应时间排序
+	sort.Slice(nodeResults, func(i, j int) bool {
+		return nodeResults[i].ResponseTime < nodeResults[j].ResponseTime
+	})
+	return nodeResults
+}
+
+// selectFastestNode 选出响应最快且链 ID 符合预期的节点（expectChainID 为 0 时不校验链 ID），
+// 节点文件中标注了预期链 ID 的节点还需与标注一致
+func selectFastestNode(nodes []rpcNode, timeout time.Duration, expectChainID int64) (NodeResult, error) {
+	results := probeAllNodes(nodeURLs(nodes), timeout)
+	checkExpectedChains(results, nodes)
+	for _, result := range healthyNodes(results) {
+		if expectChainID > 0 && result.ChainID.Int64() != expectChainID {
+			continue
+		}
+		return result, nil
+	}
+	if expectChainID > 0 {
+		return NodeResult{}, fmt.Errorf("没有可用的链 ID 为 %d 的节点", expectChainID)
+	}
+	return NodeResult{}, fmt.Errorf("没有可用的节点")
+}
+
+// autoSelectRPC 自动探测节点并返回最快节点的 URL，nodesFile 为空时探测内置的 BSC 节点
+func autoSelectRPC(nodesFile string, expectChainID int64) (string, error) {
+	nodes := defaultRPCNodes()
+	if nodesFile != "" {
+		var err error
+		nodes, err = readNodesFile(nodesFile)
+		if err != nil {
+			return "", err
+		}
+		log.Printf("正在探测节点文件 %s 中的 %d 个节点以自动选择 RPC...", nodesFile, len(nodes))
+	} else {
+		log.Printf("正在探测 %d 个内置节点以自动选择 RPC...", len(nodes))
+	}
+	node, err := selectFastestNode(nodes, 5*time.Second, expectChainID)
+	if err != nil {
+		return "", err
+	}
+	log.Printf("自动选择节点: %s (%.2f ms，链 ID: %s)",
+		node.URL, float64(node.ResponseTime.Microseconds())/1000, node.ChainID.String())
+	return node.URL, nil
 }
 
 // checkNode 检查单个节点的状态
 func checkNode(ctx context.Context, nodeURL string, results chan<- NodeResult) {
 	start := time.Now()
-	client, err := ethclient.DialContext(ctx, nodeURL)
+	client, err := dialClient(ctx, nodeURL)
 	if err != nil {
 		results <- NodeResult{URL: nodeURL, Error: err}
 		return
@@ -121,10 +264,18 @@ func checkNode(ctx context.Context, nodeURL string, results chan<- NodeResult) {
 	}
 
 	responseTime := time.Since(start)
+
+	chainID, err := client.ChainID(ctx)
+	if err != nil {
+		results <- NodeResult{URL: nodeURL, Error: err}
+		return
+	}
+
 	results <- NodeResult{
 		URL:          nodeURL,
 		ResponseTime: responseTime,
 		BlockHeight:  big.NewInt(int64(blockNumber)),
+		ChainID:      chainID,
 		Error:        nil,
 	}
 }
@@ -144,7 +295,7 @@ func outputCSV(results []NodeResult) {
 	defer writer.Flush()
 
 	// 写入表头
-	writer.Write([]string{"URL", "响应时间(ms)", "区块高度"})
+	writer.Write([]string{"URL", "响应时间(ms)", "区块高度", "链ID"})
 
 	// 写入数据
 	for _, result := range results {
@@ -152,13 +303,19 @@ func outputCSV(results []NodeResult) {
 			result.URL,
 			fmt.Sprintf("%.2f", float64(result.ResponseTime.Microseconds())/1000),
 			result.BlockHeight.String(),
+			result.ChainID.String(),
 		})
 	}
 }
 
-// outputText 以文本格式输出结果
-func outputText(results []NodeResult, showStats bool) {
-	fmt.Printf("\nBSC 节点检查结果 (共 %d 个节点):\n\n", len(results))
+// outputText 以文本格式输出结果，title 为结果标题
+func outputText(title string, results []NodeResult, showStats bool) {
+	fmt.Printf("\n%s (共 %d 个节点):\n\n", title, len(results))
+
+	if len(results) == 0 {
+		fmt.Println("没有可用的健康节点")
+		return
+	}
 
 	for i, result := range results {
 		fmt.Printf("%d. %s\n", i+1, result.URL)
@@ -181,7 +338,46 @@ func outputText(results []NodeResult, showStats bool) {
 	}
 
 	fmt.Println("\n推荐使用的节点:")
-	for i, result := range results[:3] {
+	for i, result := range results[:min(3, len(results))] {
 		fmt.Printf("%d. %s (%.2f ms)\n", i+1, result.URL, float64(result.ResponseTime.Microseconds())/1000)
 	}
 }
+
+// appendNodeHistory 将一次检查中每个节点的结果追加到历史 CSV，文件为空时先写入表头
+func appendNodeHistory(path string, checkedAt time.Time, results []NodeResult) error {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("打开历史文件失败: %v", err)
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("读取历史文件信息失败: %v", err)
+	}
+
+	writer := csv.NewWriter(file)
+	if info.Size() == 0 {
+		writer.Write([]string{"timestamp", "url", "ok", "response_ms", "block_height", "chain_id", "error"})
+	}
+
+	// 按 URL 排序，使同一时间点的行顺序稳定
+	sorted := append([]NodeResult(nil), results...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].URL < sorted[j].URL
+	})
+	timestamp := checkedAt.UTC().Format(time.RFC3339)
+	for _, result := range sorted {
+		row := []string{timestamp, result.URL, "true", "", "", "", ""}
+		if result.Error != nil {
+			row[2] = "false"
+			row[6] = result.Error.Error()
+		} else {
+			row[3] = fmt.Sprintf("%.2f", float64(result.ResponseTime.Microseconds())/1000)
+			row[4] = result.BlockHeight.String()
+			row[5] = result.ChainID.String()
+		}
+		writer.Write(row)
+	}
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// captureStdout 返回 fn 执行期间写入标准输出的内容
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	done := make(chan string)
+	go func() {
+		data, _ := io.ReadAll(r)
+		done <- string(data)
+	}()
+	fn()
+	w.Close()
+	return <-done
+}
+
+func testNodeResults(n int) []NodeResult {
+	results := make([]NodeResult, n)
+	for i := range results {
+		results[i] = NodeResult{
+			URL:          fmt.Sprintf("https://node%d.example", i+1),
+			ResponseTime: time.Duration(i+1) * 10 * time.Millisecond,
+			BlockHeight:  big.NewInt(100),
+			ChainID:      big.NewInt(56),
+		}
+	}
+	return results
+}
+
+func TestOutputText(t *testing.T) {
+	for _, n := range []int{0, 1, 2, 4} {
+		t.Run(fmt.Sprintf("%d nodes", n), func(t *testing.T) {
+			out := captureStdout(t, func() {
+				outputText("BSC 节点检查结果", testNodeResults(n), true)
+			})
+			if !strings.Contains(out, fmt.Sprintf("BSC 节点检查结果 (共 %d 个节点)", n)) {
+				t.Errorf("missing title with count %d:\n%s", n, out)
+			}
+			if n == 0 {
+				if !strings.Contains(out, "没有可用的健康节点") {
+					t.Errorf("missing empty-results message:\n%s", out)
+				}
+				if strings.Contains(out, "推荐使用的节点") {
+					t.Errorf("empty results should not list recommended nodes:\n%s", out)
+				}
+				return
+			}
+
+			_, recommended, ok := strings.Cut(out, "推荐使用的节点:")
+			if !ok {
+				t.Fatalf("missing recommended nodes:\n%s", out)
+			}
+			want := min(3, n)
+			for i := 1; i <= n; i++ {
+				line := fmt.Sprintf("%d. https://node%d.example (", i, i)
+				if got := strings.Contains(recommended, line); got != (i <= want) {
+					t.Errorf("recommended contains %q = %v, want %v:\n%s", line, got, i <= want, recommended)
+				}
+			}
+			if !strings.Contains(out, "- 最慢节点: "+fmt.Sprintf("https://node%d.example", n)) {
+				t.Errorf("stats should report node%d as slowest:\n%s", n, out)
+			}
+		})
+	}
+}
+
+// fakeRPCNode 启动只响应 eth_blockNumber 和 eth_chainId 的 JSON-RPC 节点，每次请求前等待 delay
+func fakeRPCNode(t *testing.T, chainID int64, delay time.Duration) string {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		time.Sleep(delay)
+		result := "0x64"
+		if req.Method == "eth_chainId" {
+			result = fmt.Sprintf("0x%x", chainID)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"result":"%s"}`, req.ID, result)
+	}))
+	t.Cleanup(server.Close)
+	return server.URL
+}
+
+func TestAutoSelectRPCNodesFile(t *testing.T) {
+	fastWrongChain := fakeRPCNode(t, 1, 0)
+	slowBSC := fakeRPCNode(t, 56, 50*time.Millisecond)
+	path := filepath.Join(t.TempDir(), "nodes.txt")
+	// 最快的节点标注的链 ID 与实际不一致，不应被选中
+	content := fmt.Sprintf("# 自定义节点\n%s,56\n%s\n", fastWrongChain, slowBSC)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	url, err := autoSelectRPC(path, 0)
+	if err != nil {
+		t.Fatalf("autoSelectRPC: %v", err)
+	}
+	if url != slowBSC {
+		t.Errorf("selected %s, want %s", url, slowBSC)
+	}
+
+	if _, err := autoSelectRPC(path, 97); err == nil {
+		t.Error("expected error when no node matches --expect-chain-id")
+	}
+}
@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// amountChange 是两次运行中金额不同的接收者
+type amountChange struct {
+	Address common.Address
+	Old     *big.Int
+	New     *big.Int
+}
+
+// recipientDelta 是本次接收者列表相对上一次运行的差异
+type recipientDelta struct {
+	Added     []Recipient
+	Removed   []Recipient
+	Changed   []amountChange
+	Unchanged int
+}
+
+// readPreviousRun 读取上一次运行的 JSON 或 JSONL 报告，按接收者汇总转账成功的金额
+func readPreviousRun(path string) (map[common.Address]*big.Int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取上次运行报告失败: %v", err)
+	}
+
+	var results []TransferResult
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		if err := json.Unmarshal(trimmed, &results); err != nil {
+			return nil, fmt.Errorf("解析上次运行报告失败: %v", err)
+		}
+	} else {
+		scanner := bufio.NewScanner(bytes.NewReader(trimmed))
+		lineNum := 0
+		for scanner.Scan() {
+			lineNum++
+			line := bytes.TrimSpace(scanner.Bytes())
+			if len(line) == 0 {
+				continue
+			}
+			var result TransferResult
+			if err := json.Unmarshal(line, &result); err != nil {
+				return nil, fmt.Errorf("解析上次运行报告第 %d 行失败: %v", lineNum, err)
+			}
+			results = append(results, result)
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("读取上次运行报告失败: %v", err)
+		}
+	}
+
+	previous := make(map[common.Address]*big.Int)
+	for i, result := range results {
+		if !result.IsSuccess {
+			continue
+		}
+		if !common.IsHexAddress(result.Target) {
+			return nil, fmt.Errorf("上次运行报告第 %d 条记录的接收者地址无效: %s", i+1, result.Target)
+		}
+		amount, ok := new(big.Int).SetString(result.Amount, 10)
+		if !ok {
+			return nil, fmt.Errorf("上次运行报告第 %d 条记录的金额无效: %s", i+1, result.Amount)
+		}
+		addr := common.HexToAddress(result.Target)
+		if previous[addr] == nil {
+			previous[addr] = new(big.Int)
+		}
+		previous[addr].Add(previous[addr], amount)
+	}
+	return previous, nil
+}
+
+// computeRecipientDelta 比较本次接收者与上一次运行的金额，找出新增、移除和金额变化的接收者
+func computeRecipientDelta(previous map[common.Address]*big.Int, current []Recipient) recipientDelta {
+	var delta recipientDelta
+	currentTotals := make(map[common.Address]*big.Int)
+	var order []common.Address
+	for _, recipient := range current {
+		if currentTotals[recipient.Address] == nil {
+			currentTotals[recipient.Address] = new(big.Int)
+			order = append(order, recipient.Address)
+		}
+		currentTotals[recipient.Address].Add(currentTotals[recipient.Address], recipient.Amount)
+	}
+
+	for _, addr := range order {
+		amount := currentTotals[addr]
+		old, ok := previous[addr]
+		switch {
+		case !ok:
+			delta.Added = append(delta.Added, Recipient{Address: addr, Amount: amount})
+		case old.Cmp(amount) != 0:
+			delta.Changed = append(delta.Changed, amountChange{Address: addr, Old: old, New: amount})
+		default:
+			delta.Unchanged++
+		}
+	}
+	for addr, amount := range previous {
+		if currentTotals[addr] == nil {
+			delta.Removed = append(delta.Removed, Recipient{Address: addr, Amount: amount})
+		}
+	}
+	sort.Slice(delta.Removed, func(i, j int) bool {
+		return delta.Removed[i].Address.Hex() < delta.Removed[j].Address.Hex()
+	})
+	return delta
+}
+
+// logRecipientDelta 输出与上一次运行的差异
+func logRecipientDelta(path string, delta recipientDelta, unit DisplayUnit) {
+	log.Printf("与上次运行 %s 相比: 新增 %d，移除 %d，金额变化 %d，未变化 %d",
+		path, len(delta.Added), len(delta.Removed), len(delta.Changed), delta.Unchanged)
+	for _, recipient := range delta.Added {
+		log.Printf("+ %s %s", recipient.Address.Hex(), unit.Format(recipient.Amount))
+	}
+	for _, recipient := range delta.Removed {
+		log.Printf("- %s %s", recipient.Address.Hex(), unit.Format(recipient.Amount))
+	}
+	for _, change := range delta.Changed {
+		log.Printf("~ %s %s -> %s", change.Address.Hex(), unit.Format(change.Old), unit.Format(change.New))
+	}
+}
@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+)
+
+// defaultBatchMethod 是分账合约默认的批量转账方法
+const defaultBatchMethod = "batchSend"
+
+// loadBatchABI 解析分账合约 ABI，并校验 method 的参数为 (address[], uint256[])。
+// abiJSON 为空时使用内置的 batchSend ABI。
+func loadBatchABI(abiJSON, method string) (abi.ABI, error) {
+	if abiJSON == "" {
+		abiJSON = batchTransferABI
+	}
+	if method == "" {
+		method = defaultBatchMethod
+	}
+
+	parsedABI, err := abi.JSON(strings.NewReader(abiJSON))
+	if err != nil {
+		return parsedABI, fmt.Errorf("解析 ABI 失败: %v", err)
+	}
+
+	m, ok := parsedABI.Methods[method]
+	if !ok {
+		return parsedABI, fmt.Errorf("ABI 中不存在方法 %s", method)
+	}
+	if len(m.Inputs) != 2 || m.Inputs[0].Type.String() != "address[]" || m.Inputs[1].Type.String() != "uint256[]" {
+		return parsedABI, fmt.Errorf("方法 %s 的参数类型必须为 (address[], uint256[])，实际为 %s", method, m.Sig)
+	}
+	return parsedABI, nil
+}
+
+// defaultBatchTokenMethod 是分账合约默认的批量发送代币方法
+const defaultBatchTokenMethod = "batchSendToken"
+
+// batchTransferTokenABI 是内置的 batchSendToken(address token, address[] recipients, uint256[] amounts) ABI
+const batchTransferTokenABI = `[{"inputs":[{"internalType":"address","name":"token","type":"address"},{"internalType":"address[]","name":"recipients","type":"address[]"},{"internalType":"uint256[]","name":"amounts","type":"uint256[]"}],"name":"batchSendToken","outputs":[],"stateMutability":"nonpayable","type":"function"}]`
+
+// loadBatchTokenABI 解析分账合约 ABI，并校验 method 的参数为 (address, address[], uint256[])。
+// abiJSON 为空时使用内置的 batchSendToken ABI
+func loadBatchTokenABI(abiJSON, method string) (abi.ABI, error) {
+	if abiJSON == "" {
+		abiJSON = batchTransferTokenABI
+	}
+	if method == "" {
+		method = defaultBatchTokenMethod
+	}
+
+	parsedABI, err := abi.JSON(strings.NewReader(abiJSON))
+	if err != nil {
+		return parsedABI, fmt.Errorf("解析 ABI 失败: %v", err)
+	}
+
+	m, ok := parsedABI.Methods[method]
+	if !ok {
+		return parsedABI, fmt.Errorf("ABI 中不存在方法 %s", method)
+	}
+	if len(m.Inputs) != 3 || m.Inputs[0].Type.String() != "address" || m.Inputs[1].Type.String() != "address[]" || m.Inputs[2].Type.String() != "uint256[]" {
+		return parsedABI, fmt.Errorf("代币方法 %s 的参数类型必须为 (address, address[], uint256[])，实际为 %s", method, m.Sig)
+	}
+	return parsedABI, nil
+}
+
+// readABIFile 读取 ABI JSON 文件内容
+func readABIFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("读取 ABI 文件失败: %v", err)
+	}
+	return string(data), nil
+}
@@ -0,0 +1,169 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/spf13/cobra"
+)
+
+var (
+	costMatrixRPCURL      string
+	costMatrixContract    string
+	costMatrixFrom        string
+	costMatrixCount       int
+	costMatrixBatchSize   int
+	costMatrixGasPrices   string
+	costMatrixAmount      string
+	costMatrixGasPerBatch uint64
+)
+
+// CostMatrixCmd 是估算不同 gas 价格下分发成本的命令
+var CostMatrixCmd = &cobra.Command{
+	Use:   "cost-matrix",
+	Short: "估算不同 gas 价格下批量分发的总成本",
+	Long:  `根据接收者数量、批次大小和若干 gas 价格场景，使用节点对一个完整批次的 gas 估算，输出每个场景下的总 gas 成本和每个接收者的平均成本，帮助选择合适的执行时机。`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if costMatrixCount <= 0 {
+			log.Fatal("接收者数量必须大于 0 (--count)")
+		}
+		if costMatrixBatchSize <= 0 {
+			log.Fatal("批次大小必须大于 0 (--batch-size)")
+		}
+		gasPrices, err := parseGweiList(costMatrixGasPrices)
+		if err != nil {
+			log.Fatalf("gas 价格场景无效 (--gas-prices): %v", err)
+		}
+
+		// 一个完整批次包含的接收者数量
+		batchRecipients := min(costMatrixBatchSize, costMatrixCount)
+
+		batchGas := costMatrixGasPerBatch
+		if batchGas == 0 {
+			batchGas, err = estimateRepresentativeBatchGas(batchRecipients)
+			if err != nil {
+				log.Fatalf("估算批次 gas 失败: %v（可使用 --gas-per-batch 手动指定）", err)
+			}
+			log.Printf("节点估算 %d 个接收者的批次 gas: %d", batchRecipients, batchGas)
+		}
+
+		// 最后一批不足 batchSize 时按接收者数量比例折算
+		fullBatches := costMatrixCount / batchRecipients
+		remainder := costMatrixCount % batchRecipients
+		totalGas := new(big.Int).Mul(new(big.Int).SetUint64(batchGas), big.NewInt(int64(fullBatches)))
+		if remainder > 0 {
+			partial := new(big.Int).Mul(new(big.Int).SetUint64(batchGas), big.NewInt(int64(remainder)))
+			totalGas.Add(totalGas, partial.Div(partial, big.NewInt(int64(batchRecipients))))
+		}
+		totalBatches := fullBatches
+		if remainder > 0 {
+			totalBatches++
+		}
+
+		fmt.Printf("\n接收者数量: %d，批次大小: %d，批次数: %d，预计总 gas: %s\n\n",
+			costMatrixCount, costMatrixBatchSize, totalBatches, totalGas.String())
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "Gas 价格 (Gwei)\t总成本 (BNB)\t每个接收者成本 (BNB)")
+		for _, price := range gasPrices {
+			totalCost := new(big.Int).Mul(totalGas, price)
+			perRecipient := new(big.Int).Div(totalCost, big.NewInt(int64(costMatrixCount)))
+			fmt.Fprintf(w, "%s\t%s\t%s\n",
+				new(big.Rat).SetFrac(price, big.NewInt(1e9)).FloatString(2),
+				formatEther(totalCost),
+				formatEther(perRecipient))
+		}
+		w.Flush()
+	},
+}
+
+// estimateRepresentativeBatchGas 使用占位接收者构造一个完整批次，向节点估算 gas
+func estimateRepresentativeBatchGas(recipientCount int) (uint64, error) {
+	if !common.IsHexAddress(costMatrixFrom) {
+		return 0, fmt.Errorf("估算需要一个余额充足的发送者地址 (--from)")
+	}
+	amountWei, err := parseAmount(costMatrixAmount)
+	if err != nil {
+		return 0, fmt.Errorf("转账金额无效 (--amount): %v", err)
+	}
+
+	parsedABI, err := loadBatchABI("", "")
+	if err != nil {
+		return 0, err
+	}
+
+	recipients := make([]common.Address, recipientCount)
+	amounts := make([]*big.Int, recipientCount)
+	for i := range recipients {
+		// 使用哈希派生的地址作为占位接收者，避开预编译合约地址
+		recipients[i] = common.BytesToAddress(crypto.Keccak256([]byte("cost-matrix-" + strconv.Itoa(i))))
+		amounts[i] = amountWei
+	}
+	data, err := parsedABI.Pack(defaultBatchMethod, recipients, amounts)
+	if err != nil {
+		return 0, fmt.Errorf("打包调用数据失败: %v", err)
+	}
+
+	client, err := dialClient(context.Background(), costMatrixRPCURL)
+	if err != nil {
+		return 0, fmt.Errorf("连接以太坊网络失败: %v", err)
+	}
+	// 未指定 --contract 时按所连接网络的链 ID 选择默认合约
+	contractAddress := costMatrixContract
+	if contractAddress == "" {
+		contractAddress, err = defaultSplitterContract(client)
+		if err != nil {
+			return 0, err
+		}
+	}
+	contract := common.HexToAddress(contractAddress)
+	return client.EstimateGas(context.Background(), ethereum.CallMsg{
+		From:  common.HexToAddress(costMatrixFrom),
+		To:    &contract,
+		Value: new(big.Int).Mul(amountWei, big.NewInt(int64(recipientCount))),
+		Data:  data,
+	})
+}
+
+// parseGweiList 解析逗号分隔的 Gwei 价格列表，返回以 Wei 为单位的价格
+func parseGweiList(value string) ([]*big.Int, error) {
+	var prices []*big.Int
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		price, err := parseAmount(part + "gwei")
+		if err != nil {
+			return nil, err
+		}
+		if price.Sign() <= 0 {
+			return nil, fmt.Errorf("gas 价格必须大于 0: %s", part)
+		}
+		prices = append(prices, price)
+	}
+	if len(prices) == 0 {
+		return nil, fmt.Errorf("至少需要一个 gas 价格")
+	}
+	return prices, nil
+}
+
+func init() {
+	CostMatrixCmd.Flags().StringVar(&costMatrixRPCURL, "rpc", "https://bsc-dataseed.binance.org/", "以太坊 RPC URL")
+	CostMatrixCmd.Flags().StringVar(&costMatrixContract, "contract", "", "批量转账合约地址（为空时按链 ID 使用已知的默认合约）")
+	CostMatrixCmd.Flags().StringVar(&costMatrixFrom, "from", "", "用于估算 gas 的发送者地址（需要有足够余额）")
+	CostMatrixCmd.Flags().IntVar(&costMatrixCount, "count", 0, "接收者数量")
+	CostMatrixCmd.Flags().IntVar(&costMatrixBatchSize, "batch-size", 300, "每批处理的钱包数量")
+	CostMatrixCmd.Flags().StringVar(&costMatrixGasPrices, "gas-prices", "3,5,10", "逗号分隔的 gas 价格场景 (Gwei)")
+	CostMatrixCmd.Flags().StringVar(&costMatrixAmount, "amount", "0.0001", "估算时每个接收者的转账金额 (ETH)")
+	CostMatrixCmd.Flags().Uint64Var(&costMatrixGasPerBatch, "gas-per-batch", 0, "手动指定一个完整批次的 gas（设置后不连接节点估算）")
+}
@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// 钱包 CSV 的列映射，可以是表头名称或列号（从 1 开始）
+var (
+	CSVAddressColumn  = "Address"
+	CSVKeyColumn      = "Private Key"
+	CSVMnemonicColumn = "Mnemonic" // 设置为 "-" 表示 CSV 中没有助记词列
+)
+
+// walletColumns 是解析后的列下标，Mnemonic 和 Amount 为 -1 时表示不存在
+type walletColumns struct {
+	Address  int
+	Key      int
+	Mnemonic int
+	Amount   int
+}
+
+// resolveColumn 按表头名称（不区分大小写）或列号查找列下标
+func resolveColumn(headers []string, spec string) (int, error) {
+	spec = strings.TrimSpace(spec)
+	if index, err := strconv.Atoi(spec); err == nil {
+		if index < 1 || index > len(headers) {
+			return -1, fmt.Errorf("列号 %d 超出范围，CSV 共有 %d 列", index, len(headers))
+		}
+		return index - 1, nil
+	}
+	for i, header := range headers {
+		if strings.EqualFold(strings.TrimSpace(header), spec) {
+			return i, nil
+		}
+	}
+	return -1, fmt.Errorf("找不到列 %q，CSV 表头: %v", spec, headers)
+}
+
+// resolveWalletColumns 根据 --address-column / --key-column / --mnemonic-column 解析钱包 CSV 的列
+func resolveWalletColumns(headers []string) (walletColumns, error) {
+	cols := walletColumns{Mnemonic: -1, Amount: -1}
+
+	var err error
+	if cols.Address, err = resolveColumn(headers, CSVAddressColumn); err != nil {
+		return cols, fmt.Errorf("地址列无效 (--address-column): %v", err)
+	}
+	if cols.Key, err = resolveColumn(headers, CSVKeyColumn); err != nil {
+		return cols, fmt.Errorf("私钥列无效 (--key-column): %v", err)
+	}
+	if CSVMnemonicColumn != "-" {
+		if cols.Mnemonic, err = resolveColumn(headers, CSVMnemonicColumn); err != nil {
+			return cols, fmt.Errorf("助记词列无效 (--mnemonic-column): %v", err)
+		}
+	}
+	if cols.Address == cols.Key || cols.Address == cols.Mnemonic || cols.Key == cols.Mnemonic {
+		return cols, fmt.Errorf("地址、私钥、助记词必须映射到不同的列")
+	}
+
+	// 可选的 Amount 列（以 ETH 为单位），为空时使用统一金额
+	if index, err := resolveColumn(headers, "Amount"); err == nil && index != cols.Address && index != cols.Key && index != cols.Mnemonic {
+		cols.Amount = index
+	}
+	return cols, nil
+}
+
+// readAddressesFromCSV 读取 CSV 中地址列（--address-column）的所有地址，只要求有地址列
+func readAddressesFromCSV(filePath string) ([]common.Address, error) {
+	var input io.Reader = os.Stdin
+	if filePath != stdinPath {
+		file, err := os.Open(filePath)
+		if err != nil {
+			return nil, fmt.Errorf("打开 CSV 文件失败: %v", err)
+		}
+		defer file.Close()
+		input = file
+	}
+
+	records, err := csv.NewReader(input).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("读取 CSV 文件失败: %v", err)
+	}
+	if len(records) < 2 { // 至少需要表头和一行数据
+		return nil, fmt.Errorf("CSV 文件为空或格式不正确")
+	}
+
+	column, err := resolveColumn(records[0], CSVAddressColumn)
+	if err != nil {
+		return nil, fmt.Errorf("地址列无效 (--address-column): %v", err)
+	}
+	var addresses []common.Address
+	for i, record := range records[1:] {
+		addr := strings.TrimSpace(record[column])
+		if !common.IsHexAddress(addr) {
+			return nil, fmt.Errorf("第 %d 行地址无效: %s", i+2, addr)
+		}
+		addresses = append(addresses, common.HexToAddress(addr))
+	}
+	return addresses, nil
+}
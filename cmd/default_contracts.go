@@ -0,0 +1,26 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// defaultSplitterContracts 是各链已部署的批量转账合约地址（链 ID → 合约地址）
+var defaultSplitterContracts = map[int64]string{
+	56: "0x61e0336Ba3bEd95deD28b01ef9cD015d7F32437d", // BSC 主网
+}
+
+// defaultSplitterContract 读取所连接网络的链 ID 并返回该链的默认批量转账合约地址，未知链返回错误
+func defaultSplitterContract(client *ethclient.Client) (string, error) {
+	chainID, err := client.ChainID(context.Background())
+	if err != nil {
+		return "", fmt.Errorf("获取链 ID 失败: %v", err)
+	}
+	address, ok := defaultSplitterContracts[chainID.Int64()]
+	if !ok {
+		return "", fmt.Errorf("链 ID %s 没有已知的默认批量转账合约，请通过 --contract 指定", chainID.String())
+	}
+	return address, nil
+}
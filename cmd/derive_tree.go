@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"AccountSplitting/lib"
+	"context"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+)
+
+var (
+	treeMnemonic     string
+	treeMnemonicEnv  string
+	treeStart        int
+	treeCount        int
+	treeWithBalances bool
+	treeRPCURL       string
+	treeMulticall    string
+)
+
+// TreeCmd 是输出助记词派生地址表的命令
+var TreeCmd = &cobra.Command{
+	Use:   "derive-tree",
+	Short: "输出助记词按 m/44'/60'/0'/0/i 派生的地址表",
+	Long:  `按 BIP-44 路径 m/44'/60'/0'/0/i 从助记词派生 --start 起的 --count 个地址，输出 索引 → 路径 → 地址 表格，用于确认一个种子对应哪些地址。指定 --with-balances 时同时查询每个地址的余额，便于找到资金所在的派生账户。助记词可通过 --mnemonic 或环境变量（--mnemonic-env）提供。`,
+	Run: func(cmd *cobra.Command, args []string) {
+		mnemonic := treeMnemonic
+		if mnemonic == "" && treeMnemonicEnv != "" {
+			mnemonic = os.Getenv(treeMnemonicEnv)
+		}
+		mnemonic = strings.Join(strings.Fields(mnemonic), " ")
+		if mnemonic == "" {
+			log.Fatalf("请通过 --mnemonic 或环境变量 %s 提供助记词", treeMnemonicEnv)
+		}
+		if treeStart < 0 {
+			log.Fatal("起始索引不能为负数 (--start)")
+		}
+		if treeCount <= 0 {
+			log.Fatal("派生数量必须大于 0 (--count)")
+		}
+		if treeMulticall != "" && !common.IsHexAddress(treeMulticall) {
+			log.Fatalf("无效的 Multicall3 合约地址 (--multicall): %s", treeMulticall)
+		}
+
+		addresses, err := lib.DeriveAddresses(mnemonic, uint32(treeStart), uint32(treeCount))
+		if err != nil {
+			log.Fatalf("派生地址失败: %v", err)
+		}
+
+		var balances []*big.Int
+		if treeWithBalances {
+			client, err := dialClient(context.Background(), treeRPCURL)
+			if err != nil {
+				log.Fatalf("连接以太坊网络失败: %v", err)
+			}
+			if treeMulticall != "" {
+				balances, err = fetchBalancesMulticall(client, common.HexToAddress(treeMulticall), addresses, 500)
+			} else {
+				balances, err = fetchBalancesIndividually(client, addresses, 10)
+			}
+			if err != nil {
+				log.Fatalf("查询余额失败: %v", err)
+			}
+		}
+
+		if treeWithBalances {
+			fmt.Printf("%-6s %-22s %-42s %s\n", "索引", "路径", "地址", "余额")
+		} else {
+			fmt.Printf("%-6s %-22s %s\n", "索引", "路径", "地址")
+		}
+		total := new(big.Int)
+		for i, address := range addresses {
+			index := uint32(treeStart + i)
+			if !treeWithBalances {
+				fmt.Printf("%-6d %-22s %s\n", index, lib.DerivationPath(index), address.Hex())
+				continue
+			}
+			balance := balances[i]
+			total.Add(total, balance)
+			fmt.Printf("%-6d %-22s %s %s\n", index, lib.DerivationPath(index), address.Hex(), formatEther(balance))
+		}
+		if treeWithBalances {
+			fmt.Printf("余额合计: %s\n", formatEther(total))
+		}
+	},
+}
+
+func init() {
+	TreeCmd.Flags().StringVar(&treeMnemonic, "mnemonic", "", "助记词（用引号括起来）")
+	TreeCmd.Flags().StringVar(&treeMnemonicEnv, "mnemonic-env", "MNEMONIC", "未指定 --mnemonic 时读取助记词的环境变量名")
+	TreeCmd.Flags().IntVar(&treeStart, "start", 0, "起始地址索引")
+	TreeCmd.Flags().IntVar(&treeCount, "count", 10, "派生地址数量")
+	TreeCmd.Flags().BoolVar(&treeWithBalances, "with-balances", false, "同时查询并输出每个地址的原生币余额")
+	TreeCmd.Flags().StringVar(&treeRPCURL, "rpc", "https://bsc-dataseed.binance.org/", "以太坊 RPC URL（--with-balances 时使用）")
+	TreeCmd.Flags().StringVar(&treeMulticall, "multicall", "", "Multicall3 合约地址，指定后批量查询余额")
+}
@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+)
+
+var (
+	diffLeftPath  string
+	diffRightPath string
+	diffDetails   bool
+)
+
+// walletsByAddress 按校验和格式的地址索引钱包，保留文件中的顺序
+func walletsByAddress(wallets []WalletInfo) (map[string]WalletInfo, []string, error) {
+	index := make(map[string]WalletInfo, len(wallets))
+	var order []string
+	for i, wallet := range wallets {
+		if !common.IsHexAddress(wallet.Address) {
+			return nil, nil, fmt.Errorf("第 %d 行地址无效: %s", i+2, wallet.Address)
+		}
+		addr := common.HexToAddress(wallet.Address).Hex()
+		if _, ok := index[addr]; !ok {
+			order = append(order, addr)
+		}
+		index[addr] = wallet
+	}
+	return index, order, nil
+}
+
+// normalizeKey 统一私钥格式（去掉 0x 前缀并转为小写）
+func normalizeKey(key string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(key), "0x"))
+}
+
+// DiffCmd 是比较两个钱包 CSV 的命令
+var DiffCmd = &cobra.Command{
+	Use:   "diff",
+	Short: "比较两个钱包 CSV 是否描述相同的钱包",
+	Long:  `读取两个钱包 CSV，按地址（不区分大小写）比较，报告只存在于其中一个文件的地址，以及相同地址但私钥或助记词不同的行。`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if diffLeftPath == "" || diffRightPath == "" {
+			log.Fatal("请提供两个钱包 CSV 文件路径 (--left 和 --right)")
+		}
+
+		leftWallets, err := readWalletsFromCSV(diffLeftPath)
+		if err != nil {
+			log.Fatalf("读取 %s 失败: %v", diffLeftPath, err)
+		}
+		rightWallets, err := readWalletsFromCSV(diffRightPath)
+		if err != nil {
+			log.Fatalf("读取 %s 失败: %v", diffRightPath, err)
+		}
+		left, leftOrder, err := walletsByAddress(leftWallets)
+		if err != nil {
+			log.Fatalf("%s: %v", diffLeftPath, err)
+		}
+		right, rightOrder, err := walletsByAddress(rightWallets)
+		if err != nil {
+			log.Fatalf("%s: %v", diffRightPath, err)
+		}
+
+		var onlyLeft, onlyRight, mismatched []string
+		for _, addr := range leftOrder {
+			other, ok := right[addr]
+			if !ok {
+				onlyLeft = append(onlyLeft, addr)
+				continue
+			}
+			wallet := left[addr]
+			var fields []string
+			if normalizeKey(wallet.PrivateKey) != normalizeKey(other.PrivateKey) {
+				fields = append(fields, "私钥")
+			}
+			if strings.Join(strings.Fields(wallet.Mnemonic), " ") != strings.Join(strings.Fields(other.Mnemonic), " ") {
+				fields = append(fields, "助记词")
+			}
+			if len(fields) > 0 {
+				mismatched = append(mismatched, fmt.Sprintf("%s (%s不同)", addr, strings.Join(fields, "、")))
+			}
+		}
+		for _, addr := range rightOrder {
+			if _, ok := left[addr]; !ok {
+				onlyRight = append(onlyRight, addr)
+			}
+		}
+
+		fmt.Printf("%s: %d 个地址，%s: %d 个地址\n", diffLeftPath, len(left), diffRightPath, len(right))
+		fmt.Printf("只在 %s 中: %d\n", diffLeftPath, len(onlyLeft))
+		fmt.Printf("只在 %s 中: %d\n", diffRightPath, len(onlyRight))
+		fmt.Printf("地址相同但内容不同: %d\n", len(mismatched))
+
+		if diffDetails {
+			printDiffList("只在 "+diffLeftPath+" 中", onlyLeft)
+			printDiffList("只在 "+diffRightPath+" 中", onlyRight)
+			printDiffList("地址相同但内容不同", mismatched)
+		}
+
+		if len(onlyLeft) == 0 && len(onlyRight) == 0 && len(mismatched) == 0 {
+			fmt.Println("两个文件描述的钱包完全一致")
+		}
+	},
+}
+
+func printDiffList(title string, items []string) {
+	if len(items) == 0 {
+		return
+	}
+	fmt.Printf("\n%s:\n", title)
+	for _, item := range items {
+		fmt.Printf("- %s\n", item)
+	}
+}
+
+func init() {
+	DiffCmd.Flags().StringVar(&diffLeftPath, "left", "", "第一个钱包 CSV 文件路径")
+	DiffCmd.Flags().StringVar(&diffRightPath, "right", "", "第二个钱包 CSV 文件路径")
+	DiffCmd.Flags().BoolVar(&diffDetails, "details", false, "输出每个差异地址的详细列表")
+}
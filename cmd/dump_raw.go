@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// appendRawTransaction 将已签名交易的 RLP 编码以十六进制追加写入文件，每行一笔，
+// 格式与 prepare-txs 的输出一致，可直接用 broadcast --raw-file 重新广播
+func appendRawTransaction(path string, tx *types.Transaction) error {
+	raw, err := tx.MarshalBinary()
+	if err != nil {
+		return fmt.Errorf("编码交易失败: %v", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("创建目录失败: %v", err)
+	}
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("打开原始交易文件失败: %v", err)
+	}
+	defer file.Close()
+
+	if _, err := fmt.Fprintln(file, hexutil.Encode(raw)); err != nil {
+		return fmt.Errorf("写入原始交易失败: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"AccountSplitting/lib"
+	"encoding/csv"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	cryptCSVPath     string
+	cryptOutputPath  string
+	cryptPasswordEnv string
+)
+
+// secretColumns 返回钱包 CSV 中需要加密的列：私钥列和（存在时）助记词列
+func secretColumns(headers []string) ([]int, error) {
+	key, err := resolveColumn(headers, CSVKeyColumn)
+	if err != nil {
+		return nil, fmt.Errorf("私钥列无效 (--key-column): %v", err)
+	}
+	columns := []int{key}
+	if CSVMnemonicColumn != "-" {
+		if mnemonic, err := resolveColumn(headers, CSVMnemonicColumn); err == nil && mnemonic != key {
+			columns = append(columns, mnemonic)
+		}
+	}
+	return columns, nil
+}
+
+// transformSecretColumns 读取钱包 CSV，对私钥和助记词列的每个非空单元格应用 transform 后写入 outputPath（权限 0600），返回处理的行数
+func transformSecretColumns(inputPath, outputPath string, transform func(string) (string, error)) (int, error) {
+	file, err := os.Open(inputPath)
+	if err != nil {
+		return 0, fmt.Errorf("打开 CSV 文件失败: %v", err)
+	}
+	records, err := csv.NewReader(file).ReadAll()
+	file.Close()
+	if err != nil {
+		return 0, fmt.Errorf("读取 CSV 文件失败: %v", err)
+	}
+	if len(records) < 2 {
+		return 0, fmt.Errorf("CSV 文件为空或格式不正确")
+	}
+	columns, err := secretColumns(records[0])
+	if err != nil {
+		return 0, err
+	}
+
+	for i, record := range records[1:] {
+		for _, column := range columns {
+			if column >= len(record) || strings.TrimSpace(record[column]) == "" {
+				continue
+			}
+			value, err := transform(strings.TrimSpace(record[column]))
+			if err != nil {
+				return 0, fmt.Errorf("第 %d 行 %s 列: %v", i+2, records[0][column], err)
+			}
+			record[column] = value
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
+		return 0, fmt.Errorf("创建输出目录失败: %v", err)
+	}
+	out, err := os.OpenFile(outputPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return 0, fmt.Errorf("创建输出文件失败: %v", err)
+	}
+	defer out.Close()
+	writer := csv.NewWriter(out)
+	writer.WriteAll(records)
+	if err := writer.Error(); err != nil {
+		return 0, fmt.Errorf("写入输出文件失败: %v", err)
+	}
+	return len(records) - 1, nil
+}
+
+// cryptArgs 校验加解密命令的参数，返回口令和输出路径（未指定时在输入文件名后加 suffix）
+func cryptArgs(suffix string) (string, string) {
+	if cryptCSVPath == "" {
+		log.Fatal("请提供钱包 CSV 文件路径 (--csv)")
+	}
+	passphrase := os.Getenv(cryptPasswordEnv)
+	if passphrase == "" {
+		log.Fatalf("请通过环境变量 %s 提供加密口令", cryptPasswordEnv)
+	}
+	output := cryptOutputPath
+	if output == "" {
+		ext := filepath.Ext(cryptCSVPath)
+		output = strings.TrimSuffix(cryptCSVPath, ext) + suffix + ext
+	}
+	if filepath.Clean(output) == filepath.Clean(cryptCSVPath) {
+		log.Fatal("输出文件不能与输入文件相同")
+	}
+	return passphrase, output
+}
+
+// EncryptCmd 是加密明文钱包 CSV 的命令
+var EncryptCmd = &cobra.Command{
+	Use:   "encrypt-csv",
+	Short: "加密明文钱包 CSV 中的私钥和助记词",
+	Long:  `读取明文钱包 CSV，使用环境变量中的口令派生密钥（scrypt），以 AES-GCM 加密私钥列和助记词列，其余列保持不变，写入新的 CSV 文件。加密后的单元格以 enc: 开头，可用 decrypt-csv 还原。`,
+	Run: func(cmd *cobra.Command, args []string) {
+		passphrase, output := cryptArgs("_encrypted")
+		encrypter, err := lib.NewFieldEncrypter(passphrase)
+		if err != nil {
+			log.Fatal(err)
+		}
+		rows, err := transformSecretColumns(cryptCSVPath, output, func(value string) (string, error) {
+			if lib.IsEncryptedField(value) {
+				return "", fmt.Errorf("已经是加密内容")
+			}
+			return encrypter.Encrypt(value)
+		})
+		if err != nil {
+			log.Fatalf("加密失败: %v", err)
+		}
+		log.Printf("加密完成，%d 个钱包已写入 %s，请妥善保管口令并删除明文文件", rows, output)
+	},
+}
+
+// DecryptCmd 是还原 encrypt-csv 加密的钱包 CSV 的命令
+var DecryptCmd = &cobra.Command{
+	Use:   "decrypt-csv",
+	Short: "解密 encrypt-csv 加密的钱包 CSV",
+	Long:  `读取 encrypt-csv 生成的加密钱包 CSV，使用环境变量中的口令解密私钥列和助记词列，写入新的明文 CSV 文件（权限 0600）。口令错误时不会写出任何内容。`,
+	Run: func(cmd *cobra.Command, args []string) {
+		passphrase, output := cryptArgs("_decrypted")
+		decrypter := lib.NewFieldDecrypter(passphrase)
+		rows, err := transformSecretColumns(cryptCSVPath, output, func(value string) (string, error) {
+			if !lib.IsEncryptedField(value) {
+				return "", fmt.Errorf("不是加密内容")
+			}
+			return decrypter.Decrypt(value)
+		})
+		if err != nil {
+			log.Fatalf("解密失败: %v", err)
+		}
+		log.Printf("解密完成，%d 个钱包已写入 %s", rows, output)
+	},
+}
+
+func init() {
+	for _, c := range []*cobra.Command{EncryptCmd, DecryptCmd} {
+		c.Flags().StringVar(&cryptCSVPath, "csv", "", "输入钱包 CSV 文件路径")
+		c.Flags().StringVarP(&cryptOutputPath, "output", "o", "", "输出 CSV 文件路径（为空时在输入文件名后加 _encrypted / _decrypted）")
+		c.Flags().StringVar(&cryptPasswordEnv, "password-env", "WALLET_CSV_PASSWORD", "读取加密口令的环境变量名")
+		c.MarkFlagRequired("csv")
+	}
+}
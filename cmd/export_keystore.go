@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+)
+
+var (
+	keystoreCSVPath      string
+	keystoreOutputDir    string
+	keystorePasswordEnv  string
+	keystoreScryptPreset string
+	keystoreScryptN      int
+	keystoreScryptP      int
+	keystoreMaxWallets   int
+)
+
+// resolveScryptParams 根据预设和显式参数确定 scrypt 的 N 和 P，显式参数为 0 时使用预设值
+func resolveScryptParams(preset string, n, p int) (int, int, error) {
+	var presetN, presetP int
+	switch preset {
+	case "", "standard":
+		presetN, presetP = keystore.StandardScryptN, keystore.StandardScryptP
+	case "light":
+		presetN, presetP = keystore.LightScryptN, keystore.LightScryptP
+	default:
+		return 0, 0, fmt.Errorf("不支持的 scrypt 预设: %s (可选 light, standard)", preset)
+	}
+	if n == 0 {
+		n = presetN
+	}
+	if p == 0 {
+		p = presetP
+	}
+	if n <= 1 || n&(n-1) != 0 {
+		return 0, 0, fmt.Errorf("scrypt N 必须是大于 1 的 2 的幂: %d", n)
+	}
+	if p <= 0 {
+		return 0, 0, fmt.Errorf("scrypt P 必须大于 0: %d", p)
+	}
+	return n, p, nil
+}
+
+// exportKeystore 将私钥按 scrypt 参数加密为 keystore JSON，写入 dir 下与 geth 相同命名的文件
+func exportKeystore(privateKeyHex, password, dir string, scryptN, scryptP int) (string, error) {
+	privateKey, err := crypto.HexToECDSA(strings.TrimPrefix(strings.TrimSpace(privateKeyHex), "0x"))
+	if err != nil {
+		return "", fmt.Errorf("解析私钥失败: %v", err)
+	}
+	id, err := uuid.NewRandom()
+	if err != nil {
+		return "", fmt.Errorf("生成 keystore ID 失败: %v", err)
+	}
+	key := &keystore.Key{
+		Id:         id,
+		Address:    crypto.PubkeyToAddress(privateKey.PublicKey),
+		PrivateKey: privateKey,
+	}
+	data, err := keystore.EncryptKey(key, password, scryptN, scryptP)
+	if err != nil {
+		return "", fmt.Errorf("加密私钥失败: %v", err)
+	}
+	name := fmt.Sprintf("UTC--%s--%x", time.Now().UTC().Format("2006-01-02T15-04-05.000000000Z"), key.Address[:])
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return "", fmt.Errorf("写入 keystore 文件失败: %v", err)
+	}
+	return path, nil
+}
+
+// ExportKeystoreCmd 是把 CSV 中的私钥导出为 keystore 文件的命令
+var ExportKeystoreCmd = &cobra.Command{
+	Use:   "export-keystore",
+	Short: "将钱包 CSV 中的私钥导出为加密的 keystore 文件",
+	Long:  `读取钱包 CSV，使用环境变量中的密码把每个私钥加密为 geth 兼容的 keystore JSON 文件。可通过 --scrypt-preset 或 --scrypt-n/--scrypt-p 调整加密强度：测试时使用 light 加快速度，生产环境使用 standard。`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if keystoreCSVPath == "" {
+			log.Fatal("请提供钱包 CSV 文件路径 (--csv)")
+		}
+		password := os.Getenv(keystorePasswordEnv)
+		if password == "" {
+			log.Fatalf("请通过环境变量 %s 提供 keystore 密码", keystorePasswordEnv)
+		}
+		scryptN, scryptP, err := resolveScryptParams(keystoreScryptPreset, keystoreScryptN, keystoreScryptP)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		wallets, err := readWalletsFromCSV(keystoreCSVPath)
+		if err != nil {
+			log.Fatalf("读取钱包 CSV 文件失败: %v", err)
+		}
+		if keystoreMaxWallets > 0 && len(wallets) > keystoreMaxWallets {
+			wallets = wallets[:keystoreMaxWallets]
+		}
+		if err := os.MkdirAll(keystoreOutputDir, 0700); err != nil {
+			log.Fatalf("创建输出目录失败: %v", err)
+		}
+
+		log.Printf("导出 %d 个钱包，scrypt 参数 N=%d，P=%d", len(wallets), scryptN, scryptP)
+		for i, wallet := range wallets {
+			path, err := exportKeystore(wallet.PrivateKey, password, keystoreOutputDir, scryptN, scryptP)
+			if err != nil {
+				log.Fatalf("第 %d 个钱包 %s 导出失败: %v", i+1, wallet.Address, err)
+			}
+			log.Printf("[%d/%d] %s -> %s", i+1, len(wallets), wallet.Address, path)
+		}
+		log.Printf("导出完成，keystore 文件已写入 %s", keystoreOutputDir)
+	},
+}
+
+func init() {
+	ExportKeystoreCmd.Flags().StringVar(&keystoreCSVPath, "csv", "", "钱包 CSV 文件路径")
+	ExportKeystoreCmd.Flags().StringVarP(&keystoreOutputDir, "output-dir", "o", "keystores", "keystore 文件输出目录")
+	ExportKeystoreCmd.Flags().StringVar(&keystorePasswordEnv, "password-env", "KEYSTORE_PASSWORD", "读取 keystore 密码的环境变量名")
+	ExportKeystoreCmd.Flags().StringVar(&keystoreScryptPreset, "scrypt-preset", "standard", "scrypt 参数预设 (light, standard)")
+	ExportKeystoreCmd.Flags().IntVar(&keystoreScryptN, "scrypt-n", 0, "scrypt N 参数，必须是 2 的幂 (0 表示使用预设值)")
+	ExportKeystoreCmd.Flags().IntVar(&keystoreScryptP, "scrypt-p", 0, "scrypt P 参数 (0 表示使用预设值)")
+	ExportKeystoreCmd.Flags().IntVar(&keystoreMaxWallets, "max-wallets", 0, "最大导出钱包数量 (0 表示不限制)")
+}
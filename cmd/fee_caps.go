@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// feeCaps 是 EIP-1559 交易的最高费用和优先费
+type feeCaps struct {
+	FeeCap *big.Int // maxFeePerGas
+	TipCap *big.Int // maxPriorityFeePerGas
+}
+
+// resolveFeeCaps 根据 --max-fee-gwei / --max-priority-gwei 确定 EIP-1559 费用上限，两者都未设置时返回 nil（使用传统 gas 价格）。
+// 只设置其中一个时，优先费取节点建议值，最高费用取 2 倍基础费用加优先费
+func resolveFeeCaps(client *ethclient.Client, maxFeeGwei, maxPriorityGwei float64) (*feeCaps, error) {
+	if maxFeeGwei < 0 || maxPriorityGwei < 0 {
+		return nil, fmt.Errorf("费用上限不能为负数 (--max-fee-gwei, --max-priority-gwei)")
+	}
+	if maxFeeGwei == 0 && maxPriorityGwei == 0 {
+		return nil, nil
+	}
+
+	ctx := context.Background()
+	caps := &feeCaps{}
+	if maxPriorityGwei > 0 {
+		caps.TipCap = gweiToWei(maxPriorityGwei)
+	} else {
+		tip, err := client.SuggestGasTipCap(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("获取建议优先费失败: %v", err)
+		}
+		caps.TipCap = tip
+	}
+	if maxFeeGwei > 0 {
+		caps.FeeCap = gweiToWei(maxFeeGwei)
+	} else {
+		header, err := client.HeaderByNumber(ctx, nil)
+		if err != nil {
+			return nil, fmt.Errorf("获取最新区块头失败: %v", err)
+		}
+		if header.BaseFee == nil {
+			return nil, fmt.Errorf("当前链不支持 EIP-1559（区块没有基础费用）")
+		}
+		caps.FeeCap = new(big.Int).Add(new(big.Int).Mul(header.BaseFee, big.NewInt(2)), caps.TipCap)
+	}
+	if caps.TipCap.Cmp(caps.FeeCap) > 0 {
+		return nil, fmt.Errorf("优先费 %.4f Gwei 不能高于最高费用 %.4f Gwei",
+			float64(caps.TipCap.Int64())/1e9, float64(caps.FeeCap.Int64())/1e9)
+	}
+	return caps, nil
+}
+
+// newTx 创建 EIP-1559 交易
+func (c *feeCaps) newTx(chainID *big.Int, nonce uint64, to common.Address, amount *big.Int, gasLimit uint64, data []byte) *types.Transaction {
+	return types.NewTx(&types.DynamicFeeTx{
+		ChainID:   chainID,
+		Nonce:     nonce,
+		GasTipCap: c.TipCap,
+		GasFeeCap: c.FeeCap,
+		Gas:       gasLimit,
+		To:        &to,
+		Value:     amount,
+		Data:      data,
+	})
+}
+
+// bump 按百分比同时提高最高费用和优先费，用于替换交易
+func (c *feeCaps) bump(percent int) *feeCaps {
+	return &feeCaps{FeeCap: bumpGasPrice(c.FeeCap, percent), TipCap: bumpGasPrice(c.TipCap, percent)}
+}
+
+func (c *feeCaps) String() string {
+	return fmt.Sprintf("最高费用 %.4f Gwei，优先费 %.4f Gwei", float64(c.FeeCap.Int64())/1e9, float64(c.TipCap.Int64())/1e9)
+}
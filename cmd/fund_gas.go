@@ -0,0 +1,213 @@
+package cmd
+
+import (
+	"AccountSplitting/lib"
+	"context"
+	"crypto/ecdsa"
+	"log"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/spf13/cobra"
+)
+
+var (
+	fundGasRPCURL        string
+	fundGasCSVPath       string
+	fundGasSenderCSVPath string
+	fundGasSenderIndex   int
+	fundGasAmount        string
+	fundGasMinGas        string
+	fundGasMultiplier    float64
+	fundGasMaxWallets    int
+)
+
+// fundGasTransferGas 是普通转账的 gas 消耗
+const fundGasTransferGas = 21000
+
+// GasFundCmd 是为余额不足以支付 gas 的钱包补充原生币的命令
+var GasFundCmd = &cobra.Command{
+	Use:   "fund-gas",
+	Short: "为原生币余额不足的钱包补充 gas",
+	Long:  `检查 CSV 中每个钱包的原生币余额，对低于 --min-gas 的钱包由发送者钱包各转入固定的少量原生币，等待所有交易确认后输出补充结果，之后即可对这些钱包执行归集。`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if fundGasCSVPath == "" {
+			log.Fatal("请提供钱包 CSV 文件路径 (--csv)")
+		}
+		if fundGasSenderIndex < 0 {
+			log.Fatal("发送者钱包索引不能为负数 (--sender-index)")
+		}
+		amountWei, err := parseAmount(fundGasAmount)
+		if err != nil {
+			log.Fatalf("无效的补充金额 (--amount): %v", err)
+		}
+		if amountWei.Sign() <= 0 {
+			log.Fatal("补充金额必须大于 0 (--amount)")
+		}
+		minGasWei, err := parseAmount(fundGasMinGas)
+		if err != nil {
+			log.Fatalf("无效的最低 gas 余额 (--min-gas): %v", err)
+		}
+
+		wallets, err := readWalletsFromCSV(fundGasCSVPath)
+		if err != nil {
+			log.Fatalf("读取钱包 CSV 文件失败: %v", err)
+		}
+		if fundGasMaxWallets > 0 && len(wallets) > fundGasMaxWallets {
+			wallets = wallets[:fundGasMaxWallets]
+		}
+		senderWallets, err := readWalletsFromCSV(fundGasSenderCSVPath)
+		if err != nil {
+			log.Fatalf("读取发送者钱包 CSV 文件失败: %v", err)
+		}
+		if fundGasSenderIndex >= len(senderWallets) {
+			log.Fatalf("发送者钱包索引超出范围 (0-%d)", len(senderWallets)-1)
+		}
+		privateKey, err := crypto.HexToECDSA(strings.TrimPrefix(senderWallets[fundGasSenderIndex].PrivateKey, "0x"))
+		if err != nil {
+			log.Fatalf("解析发送者私钥失败: %v", err)
+		}
+		fromAddress := crypto.PubkeyToAddress(privateKey.PublicKey)
+
+		ctx := context.Background()
+		client, err := dialClient(ctx, fundGasRPCURL)
+		if err != nil {
+			log.Fatalf("连接以太坊网络失败: %v", err)
+		}
+		chainID, err := client.ChainID(ctx)
+		if err != nil {
+			log.Fatalf("获取链 ID 失败: %v", err)
+		}
+
+		// 找出余额低于阈值的钱包
+		var targets []common.Address
+		for i, wallet := range wallets {
+			if !common.IsHexAddress(wallet.Address) {
+				log.Fatalf("第 %d 个钱包地址无效: %s", i+1, wallet.Address)
+			}
+			addr := common.HexToAddress(wallet.Address)
+			balance, err := client.BalanceAt(ctx, addr, nil)
+			if err != nil {
+				log.Fatalf("获取 %s 余额失败: %v", addr.Hex(), err)
+			}
+			if balance.Cmp(minGasWei) < 0 {
+				targets = append(targets, addr)
+			}
+		}
+		log.Printf("共 %d 个钱包，其中 %d 个余额低于 %s，需要补充 gas", len(wallets), len(targets), formatEther(minGasWei))
+		if len(targets) == 0 {
+			return
+		}
+
+		suggestedGasPrice, err := client.SuggestGasPrice(ctx)
+		if err != nil {
+			log.Fatalf("获取网络 gas 价格失败: %v", err)
+		}
+		gasPriceWei := new(big.Int).Mul(suggestedGasPrice, big.NewInt(int64(fundGasMultiplier*10000)))
+		gasPriceWei.Div(gasPriceWei, big.NewInt(10000))
+
+		// 发送前检查发送者余额是否足够
+		perTx := new(big.Int).Add(amountWei, new(big.Int).Mul(gasPriceWei, big.NewInt(fundGasTransferGas)))
+		required := new(big.Int).Mul(perTx, big.NewInt(int64(len(targets))))
+		senderBalance, err := client.BalanceAt(ctx, fromAddress, nil)
+		if err != nil {
+			log.Fatalf("获取发送者余额失败: %v", err)
+		}
+		if senderBalance.Cmp(required) < 0 {
+			log.Fatalf("发送者余额不足: 需要 %s，当前 %s", formatEther(required), formatEther(senderBalance))
+		}
+
+		log.Printf("配置信息:")
+		log.Printf("- 发送者钱包: %s (索引: %d)", fromAddress.Hex(), fundGasSenderIndex)
+		log.Printf("- 每个钱包补充: %s", formatEther(amountWei))
+		log.Printf("- Gas 价格: %.4f Gwei，预计最多花费: %s", float64(gasPriceWei.Int64())/1e9, formatEther(required))
+
+		amounts := make([]*big.Int, len(targets))
+		for i := range amounts {
+			amounts[i] = amountWei
+		}
+		funded, totalSpent := sendGasFunding(client, chainID, privateKey, targets, amounts, gasPriceWei)
+		failCount := len(targets) - len(funded)
+
+		log.Printf("\n补充完成！成功: %d，失败: %d，总花费（含手续费）: %s", len(funded), failCount, formatEther(totalSpent))
+		if len(funded) > 0 {
+			log.Printf("已补充 gas 的钱包:")
+			for _, addr := range funded {
+				log.Printf("- %s", addr.Hex())
+			}
+		}
+	},
+}
+
+// sendGasFunding 由 privateKey 对应的钱包依次向 targets 转入对应金额的原生币，nonce 在本地递增，
+// 全部发送后再等待确认。返回补充成功的地址和总花费（含手续费）
+func sendGasFunding(client *ethclient.Client, chainID *big.Int, privateKey *ecdsa.PrivateKey, targets []common.Address, amounts []*big.Int, gasPriceWei *big.Int) ([]common.Address, *big.Int) {
+	ctx := context.Background()
+	nonces := lib.NewNonceManager(client, crypto.PubkeyToAddress(privateKey.PublicKey))
+	signer := types.NewEIP155Signer(chainID)
+	var sent []*types.Transaction
+	var sentIndexes []int
+	for i, target := range targets {
+		nonce, err := nonces.Next(ctx)
+		if err != nil {
+			log.Printf("获取 nonce 失败，停止发送补充交易: %v", err)
+			break
+		}
+		tx := types.NewTransaction(nonce, target, amounts[i], fundGasTransferGas, gasPriceWei, nil)
+		signedTx, err := types.SignTx(tx, signer, privateKey)
+		if err != nil {
+			log.Printf("[%d/%d] 签名交易失败: %v", i+1, len(targets), err)
+			continue
+		}
+		if err := client.SendTransaction(ctx, signedTx); err != nil {
+			log.Printf("[%d/%d] 向 %s 发送失败: %v", i+1, len(targets), target.Hex(), err)
+			if err := nonces.Reset(ctx); err != nil {
+				log.Printf("重新同步 nonce 失败，停止发送补充交易: %v", err)
+				break
+			}
+			continue
+		}
+		log.Printf("[%d/%d] 已向 %s 发送补充交易: %s", i+1, len(targets), target.Hex(), signedTx.Hash().Hex())
+		sent = append(sent, signedTx)
+		sentIndexes = append(sentIndexes, i)
+	}
+
+	log.Printf("等待 %d 笔补充交易确认...", len(sent))
+	totalSpent := new(big.Int)
+	var funded []common.Address
+	for i, tx := range sent {
+		target := targets[sentIndexes[i]]
+		receipt, err := bind.WaitMined(ctx, client, tx)
+		if err != nil {
+			log.Printf("等待 %s 的交易确认失败: %v", target.Hex(), err)
+			continue
+		}
+		fee := new(big.Int).Mul(new(big.Int).SetUint64(receipt.GasUsed), gasPriceWei)
+		totalSpent.Add(totalSpent, fee)
+		if receipt.Status != types.ReceiptStatusSuccessful {
+			log.Printf("向 %s 的补充交易执行失败: %s", target.Hex(), tx.Hash().Hex())
+			continue
+		}
+		totalSpent.Add(totalSpent, amounts[sentIndexes[i]])
+		funded = append(funded, target)
+	}
+	return funded, totalSpent
+}
+
+func init() {
+	GasFundCmd.Flags().StringVar(&fundGasRPCURL, "rpc", "https://bsc-dataseed.binance.org/", "以太坊 RPC URL")
+	GasFundCmd.Flags().StringVar(&fundGasCSVPath, "csv", "", "需要补充 gas 的钱包 CSV 文件路径")
+	GasFundCmd.Flags().StringVar(&fundGasSenderCSVPath, "sender-csv", "wallets/senders/w1.csv", "发送者钱包 CSV 文件路径")
+	GasFundCmd.Flags().IntVar(&fundGasSenderIndex, "sender-index", 0, "发送者钱包在 CSV 中的索引")
+	GasFundCmd.Flags().StringVar(&fundGasAmount, "amount", "0.0005", "每个钱包补充的原生币数量，支持 wei/gwei/ether 单位后缀")
+	GasFundCmd.Flags().StringVar(&fundGasMinGas, "min-gas", "0.0002", "原生币余额低于该值的钱包才会补充")
+	GasFundCmd.Flags().Float64Var(&fundGasMultiplier, "gas-multiplier", 1.0001, "Gas 价格倍率 (相对于网络平均 gas 价格)")
+	GasFundCmd.Flags().IntVar(&fundGasMaxWallets, "max-wallets", 0, "最大处理钱包数量 (0 表示不限制)")
+
+	GasFundCmd.MarkFlagRequired("csv")
+}
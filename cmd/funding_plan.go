@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"AccountSplitting/lib"
+	"encoding/csv"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	fundingPlanCSVPath     string
+	fundingPlanCount       int
+	fundingPlanWithSenders bool
+	fundingPlanOutput      string
+)
+
+// FundingPlanCmd 是生成分账计划模板的命令
+var FundingPlanCmd = &cobra.Command{
+	Use:   "funding-plan",
+	Short: "生成与接收者一一对应的分账计划模板或发送者钱包",
+	Long: `纯本地操作，不连接网络：
+  --csv 指定接收者 CSV 时，输出带空 Amount 列的模板（可直接填写后用于 batch-transfer / sum）；
+  同时指定 --with-senders 时，为每个接收者生成一个新的发送者钱包并输出一一配对的文件；
+  只指定 --count 时，生成 N 个新的发送者钱包。`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if fundingPlanOutput == "" {
+			log.Fatal("请提供输出文件路径 (--output)")
+		}
+		if dir := filepath.Dir(fundingPlanOutput); dir != "" {
+			if err := os.MkdirAll(dir, 0755); err != nil {
+				log.Fatalf("创建输出目录失败: %v", err)
+			}
+		}
+
+		// 只给出数量时，生成 N 个发送者钱包
+		if fundingPlanCSVPath == "" {
+			if fundingPlanCount <= 0 {
+				log.Fatal("请提供接收者 CSV (--csv) 或发送者钱包数量 (--count)")
+			}
+			if err := lib.GmwsAndWirte(fundingPlanCount, fundingPlanOutput, lib.GenOptions{}); err != nil {
+				log.Fatalf("生成发送者钱包失败: %v", err)
+			}
+			fmt.Printf("已生成 %d 个发送者钱包，写入文件: %s\n", fundingPlanCount, fundingPlanOutput)
+			return
+		}
+
+		recipients, err := readWalletsFromCSV(fundingPlanCSVPath)
+		if err != nil {
+			log.Fatalf("读取接收者钱包 CSV 文件失败: %v", err)
+		}
+
+		file, err := os.Create(fundingPlanOutput)
+		if err != nil {
+			log.Fatalf("创建输出文件失败: %v", err)
+		}
+		defer file.Close()
+		writer := csv.NewWriter(file)
+
+		if fundingPlanWithSenders {
+			writer.Write([]string{"Recipient", "Sender Address", "Sender Private Key", "Sender Mnemonic", "Amount"})
+			for _, recipient := range recipients {
+				address, privateKey, mnemonic, err := lib.GMnemonicW()
+				if err != nil {
+					log.Fatalf("生成发送者钱包失败: %v", err)
+				}
+				writer.Write([]string{recipient.Address, address.Hex(), privateKey, mnemonic, ""})
+			}
+		} else {
+			writer.Write([]string{"Address", "Private Key", "Mnemonic", "Amount"})
+			for _, recipient := range recipients {
+				writer.Write([]string{recipient.Address, "", "", ""})
+			}
+		}
+
+		writer.Flush()
+		if err := writer.Error(); err != nil {
+			log.Fatalf("写入输出文件失败: %v", err)
+		}
+		fmt.Printf("已为 %d 个接收者生成分账计划模板，写入文件: %s\n", len(recipients), fundingPlanOutput)
+	},
+}
+
+func init() {
+	FundingPlanCmd.Flags().StringVar(&fundingPlanCSVPath, "csv", "", "接收者钱包 CSV 文件路径")
+	FundingPlanCmd.Flags().IntVarP(&fundingPlanCount, "count", "n", 0, "未指定 --csv 时生成的发送者钱包数量")
+	FundingPlanCmd.Flags().BoolVar(&fundingPlanWithSenders, "with-senders", false, "为每个接收者生成一个新的发送者钱包并一一配对")
+	FundingPlanCmd.Flags().StringVarP(&fundingPlanOutput, "output", "o", "wallets/plan.csv", "输出文件路径")
+}
@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"math/big"
+	"strings"
+)
+
+// isReplacementError 判断发送失败是否因为节点中已存在相同 nonce 的交易
+func isReplacementError(err error) bool {
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "replacement transaction underpriced") || strings.Contains(msg, "already known")
+}
+
+// isEIP155Error 判断发送失败是否因为节点不支持 EIP-155 重放保护签名（少数私有链或旧节点）
+func isEIP155Error(err error) bool {
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "invalid sender") || strings.Contains(msg, "invalid chain id") || strings.Contains(msg, "unprotected")
+}
+
+// bumpGasPrice 将 gas 价格提高 percent%
+func bumpGasPrice(gasPrice *big.Int, percent int) *big.Int {
+	bumped := new(big.Int).Mul(gasPrice, big.NewInt(int64(100+percent)))
+	return bumped.Div(bumped, big.NewInt(100))
+}
+
+// defaultGasBuffer 是估算 gas 后默认增加的缓冲百分比
+const defaultGasBuffer = 20
+
+// applyGasBuffer 在估算的 gas 上增加 percent% 的缓冲，percent 为 0 时原样使用估算值
+func applyGasBuffer(gas uint64, percent int) uint64 {
+	return gas * uint64(100+percent) / 100
+}
+
+// gweiToWei 将以 Gwei 为单位的 gas 价格转换为 Wei
+func gweiToWei(gwei float64) *big.Int {
+	wei, _ := new(big.Float).Mul(big.NewFloat(gwei), big.NewFloat(1e9)).Int(nil)
+	return wei
+}
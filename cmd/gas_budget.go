@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// gasBudget 是整个运行可花费的手续费总额（以 Wei 为单位），按每笔交易回执的实际手续费累计
+type gasBudget struct {
+	limit *big.Int
+	spent *big.Int
+}
+
+// parseMaxGasTotal 解析 --max-gas-total，为空时返回 nil（不限制）
+func parseMaxGasTotal(value string) (*gasBudget, error) {
+	if value == "" {
+		return nil, nil
+	}
+	limit, err := parseAmount(value)
+	if err != nil {
+		return nil, fmt.Errorf("手续费总额上限无效 (--max-gas-total): %v", err)
+	}
+	if limit.Sign() <= 0 {
+		return nil, fmt.Errorf("手续费总额上限必须大于 0 (--max-gas-total)")
+	}
+	return &gasBudget{limit: limit, spent: new(big.Int)}, nil
+}
+
+// remaining 返回剩余的手续费预算
+func (b *gasBudget) remaining() *big.Int {
+	return new(big.Int).Sub(b.limit, b.spent)
+}
+
+// check 判断下一笔交易按 gasLimit × gasPrice 计算的最高手续费是否超出剩余预算
+func (b *gasBudget) check(gasLimit uint64, gasPrice *big.Int) error {
+	fee := new(big.Int).Mul(new(big.Int).SetUint64(gasLimit), gasPrice)
+	if fee.Cmp(b.remaining()) > 0 {
+		return fmt.Errorf("下一笔交易最高手续费 %s 超过剩余手续费预算 %s（已花费 %s，上限 %s，--max-gas-total）",
+			formatEther(fee), formatEther(b.remaining()), formatEther(b.spent), formatEther(b.limit))
+	}
+	return nil
+}
+
+// record 按回执的 GasUsed × 实际 gas 价格累计已花费的手续费，节点未返回实际价格时使用交易的 gas 价格
+func (b *gasBudget) record(receipt *types.Receipt, tx *types.Transaction) {
+	price := receipt.EffectiveGasPrice
+	if price == nil {
+		price = tx.GasPrice()
+	}
+	b.spent.Add(b.spent, new(big.Int).Mul(new(big.Int).SetUint64(receipt.GasUsed), price))
+}
@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// fetchJSONNumber 从 HTTP JSON 接口读取数值字段，path 为以点分隔的字段路径，例如 .fast 或 result.FastGasPrice。
+// 字段可以是数字或数字字符串，返回十进制字符串
+func fetchJSONNumber(url, path string) (string, error) {
+	httpClient := &http.Client{Timeout: 10 * time.Second}
+	resp, err := httpClient.Get(url)
+	if err != nil {
+		return "", fmt.Errorf("请求接口失败: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("接口返回状态码 %d", resp.StatusCode)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return "", fmt.Errorf("读取接口响应失败: %v", err)
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(string(body)))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return "", fmt.Errorf("解析接口响应失败: %v", err)
+	}
+	for _, key := range strings.Split(strings.Trim(path, "."), ".") {
+		if key == "" {
+			continue
+		}
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return "", fmt.Errorf("字段路径 %s 无效: %s 不是对象", path, key)
+		}
+		if value, ok = object[key]; !ok {
+			return "", fmt.Errorf("接口响应中没有字段 %s", key)
+		}
+	}
+
+	switch v := value.(type) {
+	case json.Number:
+		return v.String(), nil
+	case string:
+		return strings.TrimSpace(v), nil
+	default:
+		return "", fmt.Errorf("字段 %s 不是数字: %v", path, value)
+	}
+}
+
+// fetchOracleGasPrice 从 HTTP JSON 接口读取 gas 价格（Gwei）
+func fetchOracleGasPrice(url, path string) (*big.Int, error) {
+	gwei, err := fetchJSONNumber(url, path)
+	if err != nil {
+		return nil, fmt.Errorf("读取 gas 价格接口失败: %v", err)
+	}
+	gasPrice, err := parseAmount(gwei + " gwei")
+	if err != nil {
+		return nil, fmt.Errorf("gas 价格 %s 无效: %v", gwei, err)
+	}
+	if gasPrice.Sign() <= 0 {
+		return nil, fmt.Errorf("gas 价格必须大于 0: %s", gwei)
+	}
+	return gasPrice, nil
+}
+
+// baseGasPrice 返回应用倍率前的基础 gas 价格：配置了 oracleURL 时优先使用外部接口，接口不可用时回退到节点建议价格
+func baseGasPrice(client *ethclient.Client, oracleURL, oraclePath string) (*big.Int, error) {
+	if oracleURL != "" {
+		gasPrice, err := fetchOracleGasPrice(oracleURL, oraclePath)
+		if err == nil {
+			log.Printf("使用外部 gas 价格接口: %.4f Gwei", float64(gasPrice.Int64())/1e9)
+			return gasPrice, nil
+		}
+		log.Printf("外部 gas 价格接口不可用，回退到节点建议价格: %v", err)
+	}
+	return client.SuggestGasPrice(context.Background())
+}
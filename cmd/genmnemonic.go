@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/spf13/cobra"
 )
@@ -13,12 +14,20 @@ var (
 	numMws      int
 	outCsv      string
 	mnemonicDir string
+	mwChunkSize int
+	mwProgress  bool
+	mwVerbose   bool
+	mwLogEvery  int
+	mwFormat    string
+	mwStrength  int
+	mwAppend    bool
 )
 
 // GenMnemonicCmd 是生成助记词和钱包的命令
 var GenMnemonicCmd = &cobra.Command{
-	Use:   "genmnemonic",
-	Short: "批量生成带助记词的钱包",
+	Use:     "genmnemonic",
+	Aliases: []string{"gm"},
+	Short:   "批量生成带助记词的钱包",
 	Run: func(cmd *cobra.Command, args []string) {
 		if mnemonicDir == "" {
 			mnemonicDir = "./wallets"
@@ -27,8 +36,34 @@ var GenMnemonicCmd = &cobra.Command{
 			fmt.Println("创建目录失败:", err)
 			return
 		}
+		words, ok := lib.MnemonicWordCounts[mwStrength]
+		if !ok {
+			fmt.Println("不支持的助记词强度:", mwStrength, "(可选 128, 160, 192, 224, 256)")
+			return
+		}
+		fmt.Printf("助记词强度: %d 位 (%d 个单词)\n", mwStrength, words)
+		if mwFormat != "csv" && mwFormat != "json" {
+			fmt.Println("不支持的输出格式:", mwFormat, "(可选 csv, json)")
+			return
+		}
+		// JSON 格式且未指定文件名时使用 .json 扩展名
+		if mwFormat == "json" && !cmd.Flags().Changed("output") {
+			outCsv = strings.TrimSuffix(outCsv, filepath.Ext(outCsv)) + ".json"
+		}
+		opts := lib.GenOptions{Progress: mwProgress, Verbose: mwVerbose, LogEvery: mwLogEvery, Format: mwFormat, Append: mwAppend, Strength: mwStrength}
 		outputPath := filepath.Join(mnemonicDir, outCsv)
-		err := lib.GmwsAndWirte(numMws, outputPath)
+		if mwChunkSize > 0 {
+			paths, err := writeInChunks(numMws, mwChunkSize, outputPath, func(count int, path string) error {
+				return lib.GmwsAndWirte(count, path, opts)
+			})
+			if err != nil {
+				fmt.Println("生成失败:", err)
+			} else {
+				fmt.Printf("生成成功，共写入 %d 个文件：%s\n", len(paths), strings.Join(paths, ", "))
+			}
+			return
+		}
+		err := lib.GmwsAndWirte(numMws, outputPath, opts)
 		if err != nil {
 			fmt.Println("生成失败:", err)
 		} else {
@@ -41,4 +76,11 @@ func init() {
 	GenMnemonicCmd.Flags().IntVarP(&numMws, "number", "n", 10, "生成钱包数量")
 	GenMnemonicCmd.Flags().StringVarP(&outCsv, "output", "o", "mnemonic.csv", "输出文件名")
 	GenMnemonicCmd.Flags().StringVarP(&mnemonicDir, "dir", "d", "./wallets", "输出目录")
+	GenMnemonicCmd.Flags().BoolVar(&mwProgress, "progress", false, "显示生成进度条（数量、速率、预计剩余时间）")
+	GenMnemonicCmd.Flags().BoolVarP(&mwVerbose, "verbose", "v", false, "详细模式，每生成 --log-every 个钱包输出一条日志")
+	GenMnemonicCmd.Flags().IntVar(&mwLogEvery, "log-every", 1000, "详细模式下的日志输出间隔（钱包数量）")
+	GenMnemonicCmd.Flags().IntVar(&mwStrength, "strength", 128, "助记词熵的位数：128/160/192/224/256 分别对应 12/15/18/21/24 个单词")
+	GenMnemonicCmd.Flags().BoolVar(&mwAppend, "append", false, "追加到已有的 CSV 文件（校验表头，仅新文件写入表头），而不是覆盖")
+	GenMnemonicCmd.Flags().StringVar(&mwFormat, "format", "csv", "输出格式 (csv, json)")
+	GenMnemonicCmd.Flags().IntVar(&mwChunkSize, "chunk-size", 0, "每个文件最多写入的钱包数量，超过则拆分为多个编号文件 (0 表示不拆分)")
 }
@@ -5,20 +5,28 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/spf13/cobra"
 )
 
 var (
-	numWallets int
-	outputFile string
-	walletDir  string
+	numWallets  int
+	outputFile  string
+	walletDir   string
+	chunkSize   int
+	genProgress bool
+	genVerbose  bool
+	genLogEvery int
+	genFormat   string
+	genAppend   bool
 )
 
 // GenWalletCmd 是生成钱包的命令
 var GenWalletCmd = &cobra.Command{
-	Use:   "genwallet",
-	Short: "批量生成钱包",
+	Use:     "genwallet",
+	Aliases: []string{"gw"},
+	Short:   "批量生成钱包",
 	Run: func(cmd *cobra.Command, args []string) {
 		if walletDir == "" {
 			walletDir = "./wallets"
@@ -27,8 +35,28 @@ var GenWalletCmd = &cobra.Command{
 			fmt.Println("创建目录失败:", err)
 			return
 		}
+		if genFormat != "csv" && genFormat != "json" {
+			fmt.Println("不支持的输出格式:", genFormat, "(可选 csv, json)")
+			return
+		}
+		// JSON 格式且未指定文件名时使用 .json 扩展名
+		if genFormat == "json" && !cmd.Flags().Changed("output") {
+			outputFile = strings.TrimSuffix(outputFile, filepath.Ext(outputFile)) + ".json"
+		}
+		opts := lib.GenOptions{Progress: genProgress, Verbose: genVerbose, LogEvery: genLogEvery, Format: genFormat, Append: genAppend}
 		outputPath := filepath.Join(walletDir, outputFile)
-		err := lib.GWalletsAndWirte(numWallets, outputPath)
+		if chunkSize > 0 {
+			paths, err := writeInChunks(numWallets, chunkSize, outputPath, func(count int, path string) error {
+				return lib.GWalletsAndWirte(count, path, opts)
+			})
+			if err != nil {
+				fmt.Println("生成失败:", err)
+			} else {
+				fmt.Printf("生成成功，共写入 %d 个文件：%s\n", len(paths), strings.Join(paths, ", "))
+			}
+			return
+		}
+		err := lib.GWalletsAndWirte(numWallets, outputPath, opts)
 		if err != nil {
 			fmt.Println("生成失败:", err)
 		} else {
@@ -37,8 +65,37 @@ var GenWalletCmd = &cobra.Command{
 	},
 }
 
+// chunkFilePath 生成分片文件名，例如 wallets.csv -> wallets-000.csv
+func chunkFilePath(outputPath string, index int) string {
+	ext := filepath.Ext(outputPath)
+	return fmt.Sprintf("%s-%03d%s", strings.TrimSuffix(outputPath, ext), index, ext)
+}
+
+// writeInChunks 将 total 个钱包按每个文件最多 chunkSize 个拆分写入多个编号文件
+func writeInChunks(total, chunkSize int, outputPath string, write func(int, string) error) ([]string, error) {
+	var paths []string
+	for index := 0; index*chunkSize < total; index++ {
+		count := chunkSize
+		if remaining := total - index*chunkSize; remaining < count {
+			count = remaining
+		}
+		path := chunkFilePath(outputPath, index)
+		if err := write(count, path); err != nil {
+			return paths, fmt.Errorf("写入分片文件 %s 失败: %v", path, err)
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
 func init() {
 	GenWalletCmd.Flags().IntVarP(&numWallets, "number", "n", 10, "生成钱包数量")
 	GenWalletCmd.Flags().StringVarP(&outputFile, "output", "o", "wallets.csv", "输出文件名")
 	GenWalletCmd.Flags().StringVarP(&walletDir, "dir", "d", "./wallets", "输出目录")
+	GenWalletCmd.Flags().BoolVar(&genProgress, "progress", false, "显示生成进度条（数量、速率、预计剩余时间）")
+	GenWalletCmd.Flags().BoolVarP(&genVerbose, "verbose", "v", false, "详细模式，每生成 --log-every 个钱包输出一条日志")
+	GenWalletCmd.Flags().IntVar(&genLogEvery, "log-every", 1000, "详细模式下的日志输出间隔（钱包数量）")
+	GenWalletCmd.Flags().BoolVar(&genAppend, "append", false, "追加到已有的 CSV 文件（校验表头，仅新文件写入表头），而不是覆盖")
+	GenWalletCmd.Flags().StringVar(&genFormat, "format", "csv", "输出格式 (csv, json)")
+	GenWalletCmd.Flags().IntVar(&chunkSize, "chunk-size", 0, "每个文件最多写入的钱包数量，超过则拆分为多个编号文件 (0 表示不拆分)")
 }
@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// flagInfo 是一个参数的元数据
+type flagInfo struct {
+	Name       string `json:"name"`
+	Shorthand  string `json:"shorthand,omitempty"`
+	Type       string `json:"type"`
+	Default    string `json:"default"`
+	Usage      string `json:"usage"`
+	Required   bool   `json:"required"`
+	Persistent bool   `json:"persistent"`
+	Hidden     bool   `json:"hidden,omitempty"`
+}
+
+// commandInfo 是一个命令及其子命令的元数据
+type commandInfo struct {
+	Name        string        `json:"name"`
+	Path        string        `json:"path"`
+	Use         string        `json:"use"`
+	Aliases     []string      `json:"aliases,omitempty"`
+	Short       string        `json:"short"`
+	Long        string        `json:"long,omitempty"`
+	Runnable    bool          `json:"runnable"`
+	Flags       []flagInfo    `json:"flags"`
+	Subcommands []commandInfo `json:"subcommands,omitempty"`
+}
+
+// describeFlags 收集 flags 中的所有参数，persistent 标记是否为子命令继承的全局参数
+func describeFlags(flags *pflag.FlagSet, persistent bool) []flagInfo {
+	var infos []flagInfo
+	flags.VisitAll(func(f *pflag.Flag) {
+		_, required := f.Annotations[cobra.BashCompOneRequiredFlag]
+		infos = append(infos, flagInfo{
+			Name:       f.Name,
+			Shorthand:  f.Shorthand,
+			Type:       f.Value.Type(),
+			Default:    f.DefValue,
+			Usage:      f.Usage,
+			Required:   required,
+			Persistent: persistent,
+			Hidden:     f.Hidden,
+		})
+	})
+	return infos
+}
+
+// describeCommand 递归描述命令树，跳过隐藏命令和 help
+func describeCommand(c *cobra.Command) commandInfo {
+	info := commandInfo{
+		Name:     c.Name(),
+		Path:     c.CommandPath(),
+		Use:      c.Use,
+		Aliases:  c.Aliases,
+		Short:    c.Short,
+		Long:     c.Long,
+		Runnable: c.Runnable(),
+		Flags:    append(describeFlags(c.LocalNonPersistentFlags(), false), describeFlags(c.PersistentFlags(), true)...),
+	}
+	for _, sub := range c.Commands() {
+		if !sub.IsAvailableCommand() {
+			continue
+		}
+		info.Subcommands = append(info.Subcommands, describeCommand(sub))
+	}
+	return info
+}
+
+// IntrospectCmd 以 JSON 输出整个命令树的元数据，供图形界面等外部工具自动生成表单
+var IntrospectCmd = &cobra.Command{
+	Use:    "introspect",
+	Short:  "以 JSON 输出所有命令及参数的元数据（名称、类型、默认值、说明）",
+	Long:   `遍历命令树，以 JSON 输出每个命令的名称、用法、说明、参数（类型、默认值、是否必填、是否为全局参数）和子命令，供外部工具生成界面，无需解析 --help 输出。该命令不在帮助列表中显示。`,
+	Hidden: true,
+	Run: func(cmd *cobra.Command, args []string) {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(describeCommand(cmd.Root())); err != nil {
+			log.Fatalf("输出 JSON 失败: %v", err)
+		}
+	},
+}
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// parseDelayJitter 解析延迟抖动：以 % 结尾表示延迟的百分比，否则表示秒数
+func parseDelayJitter(value string, delay time.Duration) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, nil
+	}
+	if strings.HasSuffix(value, "%") {
+		percent, err := strconv.ParseFloat(strings.TrimSpace(strings.TrimSuffix(value, "%")), 64)
+		if err != nil {
+			return 0, fmt.Errorf("无效的延迟抖动百分比: %s", value)
+		}
+		if percent < 0 || percent > 100 {
+			return 0, fmt.Errorf("延迟抖动百分比必须在 0-100 之间: %s", value)
+		}
+		return time.Duration(float64(delay) * percent / 100), nil
+	}
+	seconds, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("无效的延迟抖动: %s (示例: 20%% 或 5)", value)
+	}
+	if seconds < 0 {
+		return 0, fmt.Errorf("延迟抖动不能为负数: %s", value)
+	}
+	return time.Duration(seconds * float64(time.Second)), nil
+}
+
+// jitteredDelay 在 [delay - jitter, delay + jitter] 范围内随机取一个延迟，下限为 0
+func jitteredDelay(rng *rand.Rand, delay, jitter time.Duration) time.Duration {
+	if jitter <= 0 {
+		return delay
+	}
+	offset := time.Duration(rng.Int63n(int64(2*jitter)+1)) - jitter
+	if delay+offset < 0 {
+		return 0
+	}
+	return delay + offset
+}
+
+// newJitterRand 创建延迟抖动使用的随机数生成器，seed 为 0 时使用当前时间
+func newJitterRand(seed int64) *rand.Rand {
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	return rand.New(rand.NewSource(seed))
+}
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"AccountSplitting/lib"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+)
+
+var (
+	lookupCSVPath string
+	lookupAddress string
+)
+
+// LookupCmd 是在钱包 CSV 中查找地址所在行的命令
+var LookupCmd = &cobra.Command{
+	Use:   "lookup",
+	Short: "在钱包 CSV 中查找地址所在的行号",
+	Long:  `在钱包 CSV 中查找指定地址（不区分大小写），输出所在行号，并校验该行的私钥和助记词是否与地址匹配。`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if lookupCSVPath == "" {
+			log.Fatal("请提供钱包 CSV 文件路径 (--csv)")
+		}
+		if !common.IsHexAddress(lookupAddress) {
+			log.Fatalf("无效的地址 (--address): %s", lookupAddress)
+		}
+
+		wallets, err := readWalletsFromCSV(lookupCSVPath)
+		if err != nil {
+			log.Fatalf("读取钱包 CSV 文件失败: %v", err)
+		}
+
+		found := false
+		for i, wallet := range wallets {
+			if !strings.EqualFold(wallet.Address, lookupAddress) {
+				continue
+			}
+			found = true
+			// 第 1 行是表头
+			fmt.Printf("行 %d: %s\n", i+2, wallet.Address)
+
+			_, msg := checkAddressPrivateKey(wallet.Address, wallet.PrivateKey)
+			fmt.Printf("   私钥: %s\n", msg)
+
+			if wallet.Mnemonic == "" {
+				fmt.Println("   助记词: 无")
+				continue
+			}
+			derived, _, err := lib.DeriveFromMnemonic(wallet.Mnemonic, 0)
+			switch {
+			case err != nil:
+				fmt.Printf("   助记词: %v\n", err)
+			case strings.EqualFold(derived.Hex(), wallet.Address):
+				fmt.Println("   助记词: 地址匹配")
+			default:
+				fmt.Printf("   助记词: 与地址不匹配 (派生地址 %s)\n", derived.Hex())
+			}
+		}
+
+		if !found {
+			fmt.Printf("未在 %s 中找到地址 %s\n", lookupCSVPath, lookupAddress)
+		}
+	},
+}
+
+func init() {
+	LookupCmd.Flags().StringVar(&lookupCSVPath, "csv", "", "钱包 CSV 文件路径")
+	LookupCmd.Flags().StringVar(&lookupAddress, "address", "", "要查找的地址")
+
+	LookupCmd.MarkFlagRequired("csv")
+	LookupCmd.MarkFlagRequired("address")
+}
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+)
+
+// BatchOverride 清单中对单个批次的覆盖设置
+type BatchOverride struct {
+	Batch  int      // 批次序号（从 1 开始，与日志中的批次编号一致）
+	Amount *big.Int // 覆盖该批次每个接收者的金额（以 Wei 为单位），nil 表示不覆盖
+	Skip   bool     // 跳过该批次
+}
+
+// readBatchManifest 读取批次清单文件，格式为
+// {"batches": [{"batch": 1, "amount": "0.002"}, {"batch": 3, "skip": true}]}，金额以 ETH 为单位
+func readBatchManifest(filePath string) (map[int]BatchOverride, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("打开清单文件失败: %v", err)
+	}
+
+	var manifest struct {
+		Batches []struct {
+			Batch  int         `json:"batch"`
+			Amount json.Number `json:"amount"`
+			Skip   bool        `json:"skip"`
+		} `json:"batches"`
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("解析清单文件失败: %v", err)
+	}
+
+	overrides := make(map[int]BatchOverride)
+	for _, entry := range manifest.Batches {
+		if _, exists := overrides[entry.Batch]; exists {
+			return nil, fmt.Errorf("清单中第 %d 批重复出现", entry.Batch)
+		}
+		override := BatchOverride{Batch: entry.Batch, Skip: entry.Skip}
+		if entry.Amount != "" {
+			amount, err := parseAmount(entry.Amount.String())
+			if err != nil {
+				return nil, fmt.Errorf("清单中第 %d 批金额无效: %v", entry.Batch, err)
+			}
+			if amount.Sign() <= 0 {
+				return nil, fmt.Errorf("清单中第 %d 批金额必须大于 0", entry.Batch)
+			}
+			override.Amount = amount
+		}
+		overrides[entry.Batch] = override
+	}
+	return overrides, nil
+}
+
+// validateBatchManifest 校验清单中的批次序号都在 1..totalBatches 范围内
+func validateBatchManifest(overrides map[int]BatchOverride, totalBatches int) error {
+	for batch := range overrides {
+		if batch < 1 || batch > totalBatches {
+			return fmt.Errorf("清单中的批次 %d 超出范围 (1-%d)", batch, totalBatches)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// multicall3ABI 是批量查询余额所需的 Multicall3 函数定义
+const multicall3ABI = `[{"inputs":[{"components":[{"name":"target","type":"address"},{"name":"allowFailure","type":"bool"},{"name":"callData","type":"bytes"}],"name":"calls","type":"tuple[]"}],"name":"aggregate3","outputs":[{"components":[{"name":"success","type":"bool"},{"name":"returnData","type":"bytes"}],"name":"returnData","type":"tuple[]"}],"stateMutability":"payable","type":"function"},{"inputs":[{"name":"addr","type":"address"}],"name":"getEthBalance","outputs":[{"name":"balance","type":"uint256"}],"stateMutability":"view","type":"function"}]`
+
+// multicall3Call 对应 aggregate3 的 Call3 参数
+type multicall3Call struct {
+	Target       common.Address
+	AllowFailure bool
+	CallData     []byte
+}
+
+// multicall3Result 对应 aggregate3 的 Result 返回值
+type multicall3Result struct {
+	Success    bool
+	ReturnData []byte
+}
+
+// fetchBalancesMulticall 通过 Multicall3 的 getEthBalance 批量查询余额，每次 RPC 请求最多包含 batchSize 个地址，
+// 返回的余额与 addresses 按位置一一对应
+func fetchBalancesMulticall(client *ethclient.Client, multicall common.Address, addresses []common.Address, batchSize int) ([]*big.Int, error) {
+	parsedABI, err := abi.JSON(strings.NewReader(multicall3ABI))
+	if err != nil {
+		return nil, fmt.Errorf("解析 Multicall3 ABI 失败: %v", err)
+	}
+	if batchSize <= 0 {
+		batchSize = len(addresses)
+	}
+
+	balances := make([]*big.Int, len(addresses))
+	for start := 0; start < len(addresses); start += batchSize {
+		end := start + batchSize
+		if end > len(addresses) {
+			end = len(addresses)
+		}
+		chunk := addresses[start:end]
+
+		calls := make([]multicall3Call, len(chunk))
+		for i, addr := range chunk {
+			callData, err := parsedABI.Pack("getEthBalance", addr)
+			if err != nil {
+				return nil, fmt.Errorf("打包 getEthBalance 调用失败: %v", err)
+			}
+			calls[i] = multicall3Call{Target: multicall, CallData: callData}
+		}
+		data, err := parsedABI.Pack("aggregate3", calls)
+		if err != nil {
+			return nil, fmt.Errorf("打包 aggregate3 调用失败: %v", err)
+		}
+		output, err := client.CallContract(context.Background(), ethereum.CallMsg{To: &multicall, Data: data}, nil)
+		if err != nil {
+			return nil, fmt.Errorf("调用 Multicall3 失败: %v", err)
+		}
+		if len(output) == 0 {
+			return nil, fmt.Errorf("地址 %s 上没有 Multicall3 合约", multicall.Hex())
+		}
+
+		var results []multicall3Result
+		if err := parsedABI.UnpackIntoInterface(&results, "aggregate3", output); err != nil {
+			return nil, fmt.Errorf("解析 Multicall3 返回值失败: %v", err)
+		}
+		if len(results) != len(chunk) {
+			return nil, fmt.Errorf("Multicall3 返回 %d 个结果，请求了 %d 个", len(results), len(chunk))
+		}
+		for i, result := range results {
+			if !result.Success || len(result.ReturnData) < 32 {
+				return nil, fmt.Errorf("查询 %s 的余额失败", chunk[i].Hex())
+			}
+			balances[start+i] = new(big.Int).SetBytes(result.ReturnData[:32])
+		}
+	}
+	return balances, nil
+}
+
+// fetchBalancesIndividually 逐个并发调用 BalanceAt 查询余额，concurrency 限制同时进行的请求数。
+// 结果按输入位置写入，返回的余额与 addresses 一一对应，不受请求完成顺序影响
+func fetchBalancesIndividually(client *ethclient.Client, addresses []common.Address, concurrency int) ([]*big.Int, error) {
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	balances := make([]*big.Int, len(addresses))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	var firstErr error
+	sem := make(chan struct{}, concurrency)
+
+	for i, addr := range addresses {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, addr common.Address) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			balance, err := client.BalanceAt(context.Background(), addr, nil)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = fmt.Errorf("查询 %s 的余额失败: %v", addr.Hex(), err)
+				}
+				return
+			}
+			balances[i] = balance
+		}(i, addr)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return balances, nil
+}
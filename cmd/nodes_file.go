@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// rpcNode 是节点文件中的一个节点，ExpectChainID 为 0 表示不校验链 ID
+type rpcNode struct {
+	URL           string
+	ExpectChainID int64
+}
+
+// defaultRPCNodes 返回内置的 BSC 节点列表
+func defaultRPCNodes() []rpcNode {
+	nodes := make([]rpcNode, 0, len(defaultBSCNodes))
+	for _, url := range defaultBSCNodes {
+		nodes = append(nodes, rpcNode{URL: url})
+	}
+	return nodes
+}
+
+// nodeURLs 返回节点的 URL 列表
+func nodeURLs(nodes []rpcNode) []string {
+	urls := make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		urls = append(urls, node.URL)
+	}
+	return urls
+}
+
+// readNodesFile 读取节点文件：每行一个节点 URL，可选第二列为该节点预期的链 ID（逗号或空白分隔），
+// 空行和以 # 开头的行会被忽略
+func readNodesFile(path string) ([]rpcNode, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("打开节点文件失败: %v", err)
+	}
+	defer file.Close()
+
+	var nodes []rpcNode
+	seen := make(map[string]bool)
+	scanner := bufio.NewScanner(file)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.FieldsFunc(line, func(r rune) bool {
+			return r == ',' || r == ' ' || r == '\t'
+		})
+		if len(fields) > 2 {
+			return nil, fmt.Errorf("节点文件第 %d 行格式不正确，应为 URL 或 URL,链ID", lineNumber)
+		}
+		node := rpcNode{URL: fields[0]}
+		if len(fields) == 2 {
+			chainID, err := strconv.ParseInt(fields[1], 10, 64)
+			if err != nil || chainID <= 0 {
+				return nil, fmt.Errorf("节点文件第 %d 行链 ID 无效: %s", lineNumber, fields[1])
+			}
+			node.ExpectChainID = chainID
+		}
+		if seen[node.URL] {
+			return nil, fmt.Errorf("节点文件第 %d 行节点重复: %s", lineNumber, node.URL)
+		}
+		seen[node.URL] = true
+		nodes = append(nodes, node)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("读取节点文件失败: %v", err)
+	}
+	if len(nodes) == 0 {
+		return nil, fmt.Errorf("节点文件 %s 中没有节点", path)
+	}
+	return nodes, nil
+}
+
+// checkExpectedChains 将链 ID 与节点文件中预期值不一致的节点标记为失败
+func checkExpectedChains(results []NodeResult, nodes []rpcNode) {
+	expected := make(map[string]int64)
+	for _, node := range nodes {
+		expected[node.URL] = node.ExpectChainID
+	}
+	for i, result := range results {
+		want := expected[result.URL]
+		if result.Error != nil || want == 0 || result.ChainID.Int64() == want {
+			continue
+		}
+		results[i].Error = fmt.Errorf("链 ID 为 %s，与预期的 %d 不一致", result.ChainID, want)
+	}
+}
+
+// groupByChain 按链 ID 分组，组按链 ID 升序排列，组内保持原有顺序
+func groupByChain(results []NodeResult) [][]NodeResult {
+	groups := make(map[int64][]NodeResult)
+	var chainIDs []int64
+	for _, result := range results {
+		chainID := result.ChainID.Int64()
+		if _, ok := groups[chainID]; !ok {
+			chainIDs = append(chainIDs, chainID)
+		}
+		groups[chainID] = append(groups[chainID], result)
+	}
+	sort.Slice(chainIDs, func(i, j int) bool { return chainIDs[i] < chainIDs[j] })
+	grouped := make([][]NodeResult, 0, len(chainIDs))
+	for _, chainID := range chainIDs {
+		grouped = append(grouped, groups[chainID])
+	}
+	return grouped
+}
@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"AccountSplitting/lib"
+	"bytes"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/go-pdf/fpdf"
+	"github.com/skip2/go-qrcode"
+	"github.com/spf13/cobra"
+)
+
+var (
+	paperCSVPath  string
+	paperIndex    int
+	paperCount    int
+	paperStrength int
+	paperOutput   string
+)
+
+// paperQRSize 是纸钱包中二维码的边长（毫米）
+const paperQRSize = 45
+
+// addPaperQR 把 content 编码为二维码图片并放到当前页的 (x, y)
+func addPaperQR(pdf *fpdf.Fpdf, name, content string, x, y float64) error {
+	png, err := qrcode.Encode(content, qrcode.Medium, 512)
+	if err != nil {
+		return fmt.Errorf("生成二维码失败: %v", err)
+	}
+	options := fpdf.ImageOptions{ImageType: "PNG"}
+	pdf.RegisterImageOptionsReader(name, options, bytes.NewReader(png))
+	pdf.ImageOptions(name, x, y, paperQRSize, paperQRSize, false, options, 0, "")
+	return nil
+}
+
+// addPaperSection 输出一个带标题、文本和二维码的区块，二维码在左、文本在右
+func addPaperSection(pdf *fpdf.Fpdf, name, title, content string) error {
+	y := pdf.GetY()
+	if err := addPaperQR(pdf, name, content, 15, y); err != nil {
+		return err
+	}
+	pdf.SetXY(65, y)
+	pdf.SetFont("Helvetica", "B", 12)
+	pdf.CellFormat(0, 7, title, "", 1, "L", false, 0, "")
+	pdf.SetX(65)
+	pdf.SetFont("Courier", "", 10)
+	pdf.MultiCell(130, 5, content, "", "L", false)
+	pdf.SetY(y + paperQRSize + 8)
+	return nil
+}
+
+// addPaperWalletPage 为一个钱包输出一页纸钱包
+func addPaperWalletPage(pdf *fpdf.Fpdf, page int, wallet WalletInfo) error {
+	pdf.AddPage()
+	pdf.SetFont("Helvetica", "B", 18)
+	pdf.CellFormat(0, 10, fmt.Sprintf("Paper Wallet #%d", page), "", 1, "C", false, 0, "")
+	pdf.SetFont("Helvetica", "", 9)
+	pdf.MultiCell(0, 5, "Keep this page secret and offline. Anyone holding the private key or mnemonic controls the funds. "+
+		"Only share the address.", "", "C", false)
+	pdf.Ln(6)
+
+	if err := addPaperSection(pdf, fmt.Sprintf("address-%d", page), "Address (public, for receiving)", wallet.Address); err != nil {
+		return err
+	}
+	if err := addPaperSection(pdf, fmt.Sprintf("key-%d", page), "Private Key (SECRET)", wallet.PrivateKey); err != nil {
+		return err
+	}
+	if wallet.Mnemonic != "" {
+		if err := addPaperSection(pdf, fmt.Sprintf("mnemonic-%d", page), "Mnemonic (SECRET, path "+lib.DerivationPath(0)+")", wallet.Mnemonic); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PaperCmd 是生成可打印纸钱包 PDF 的命令
+var PaperCmd = &cobra.Command{
+	Use:   "paper-wallet",
+	Short: "生成可打印的纸钱包 PDF（地址、私钥、助记词及二维码），用于冷存储",
+	Long:  `把钱包的地址、私钥、助记词及对应的二维码写入 PDF，每页一个钱包，可打印后离线保存。指定 --csv 时使用已生成钱包中从 --index 起的 --count 个钱包，否则生成 --count 个新的助记词钱包。请在断网的电脑上运行并打印，PDF 文件包含私钥，打印后应立即删除。`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if paperCount <= 0 {
+			log.Fatal("钱包数量必须大于 0 (--count)")
+		}
+		if paperIndex < 0 {
+			log.Fatal("钱包索引不能为负数 (--index)")
+		}
+
+		log.Printf("警告: 纸钱包包含私钥和助记词，请在断网的电脑上生成并打印，不要使用网络打印机或云打印，打印后删除 PDF 文件")
+
+		var wallets []WalletInfo
+		if paperCSVPath != "" {
+			all, err := readWalletsFromCSV(paperCSVPath)
+			if err != nil {
+				log.Fatalf("读取钱包 CSV 文件失败: %v", err)
+			}
+			if paperIndex+paperCount > len(all) {
+				log.Fatalf("CSV 文件中只有 %d 个钱包，无法从索引 %d 起读取 %d 个", len(all), paperIndex, paperCount)
+			}
+			wallets = all[paperIndex : paperIndex+paperCount]
+		} else {
+			for i := 0; i < paperCount; i++ {
+				address, privateKey, mnemonic, err := lib.GMnemonicWStrength(paperStrength)
+				if err != nil {
+					log.Fatalf("生成钱包失败: %v", err)
+				}
+				wallets = append(wallets, WalletInfo{Address: address.Hex(), PrivateKey: privateKey, Mnemonic: mnemonic})
+			}
+		}
+
+		pdf := fpdf.New("P", "mm", "A4", "")
+		pdf.SetTitle("Paper Wallet", false)
+		for i, wallet := range wallets {
+			if err := addPaperWalletPage(pdf, i+1, wallet); err != nil {
+				log.Fatalf("生成第 %d 页失败: %v", i+1, err)
+			}
+		}
+
+		output := paperOutput
+		if output == "" {
+			output = fmt.Sprintf("paper_wallet_%s.pdf", time.Now().Format("20060102_150405"))
+		}
+		if dir := filepath.Dir(output); dir != "." {
+			if err := os.MkdirAll(dir, 0700); err != nil {
+				log.Fatalf("创建输出目录失败: %v", err)
+			}
+		}
+		file, err := os.OpenFile(output, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+		if err != nil {
+			log.Fatalf("创建 PDF 文件失败: %v", err)
+		}
+		if err := pdf.Output(file); err != nil {
+			file.Close()
+			log.Fatalf("写入 PDF 文件失败: %v", err)
+		}
+		if err := file.Close(); err != nil {
+			log.Fatalf("写入 PDF 文件失败: %v", err)
+		}
+
+		log.Printf("已生成 %d 页纸钱包: %s", len(wallets), output)
+		for _, wallet := range wallets {
+			log.Printf("- %s", wallet.Address)
+		}
+	},
+}
+
+func init() {
+	PaperCmd.Flags().StringVar(&paperCSVPath, "csv", "", "已生成的钱包 CSV 文件路径（为空时生成新的助记词钱包）")
+	PaperCmd.Flags().IntVar(&paperIndex, "index", 0, "使用 CSV 中从该索引开始的钱包")
+	PaperCmd.Flags().IntVarP(&paperCount, "count", "n", 1, "纸钱包数量（每页一个钱包）")
+	PaperCmd.Flags().IntVar(&paperStrength, "strength", 128, "生成新钱包时的助记词强度（位），可选 128, 160, 192, 224, 256")
+	PaperCmd.Flags().StringVarP(&paperOutput, "output", "o", "", "输出 PDF 文件路径（为空时使用 paper_wallet_时间戳.pdf，已存在时不覆盖）")
+}
@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	_ "net/http/pprof" // 注册 /debug/pprof/ 处理器
+	"time"
+)
+
+// StartPprof 在指定地址启动 pprof 调试服务，用于在生成或转账过程中采集 CPU/内存 profile。
+// pprof 没有鉴权且会暴露命令行参数和运行时信息，allowRemote 为 false 时只允许监听本机回环地址
+func StartPprof(addr string, allowRemote bool) (*http.Server, error) {
+	if !allowRemote && !isLoopbackAddr(addr) {
+		return nil, fmt.Errorf("pprof 地址 %s 不是本机回环地址，pprof 没有鉴权，确需远程访问请同时指定 --pprof-allow-remote", addr)
+	}
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("启动 pprof 服务失败: %v", err)
+	}
+	server := &http.Server{Handler: http.DefaultServeMux}
+	go func() {
+		if err := server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			log.Printf("pprof 服务异常退出: %v", err)
+		}
+	}()
+	log.Printf("pprof 服务已启动: http://%s/debug/pprof/", listener.Addr())
+	return server, nil
+}
+
+// isLoopbackAddr 判断 host:port 形式的监听地址是否只绑定本机回环地址，host 为空表示监听所有网卡
+func isLoopbackAddr(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil || host == "" {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// StopPprof 关闭 pprof 调试服务
+func StopPprof(server *http.Server) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := server.Shutdown(ctx); err != nil {
+		log.Printf("关闭 pprof 服务失败: %v", err)
+	}
+}
@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"context"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// walletState 是预取到的钱包余额和 nonce
+type walletState struct {
+	Balance *big.Int
+	Nonce   uint64
+}
+
+// prefetchWalletStates 并发查询所有地址的余额和 pending nonce，concurrency 限制同时进行的请求数。
+// 查询失败的地址不会出现在返回结果中，调用方需要回退到实时查询。
+func prefetchWalletStates(client *ethclient.Client, addresses []common.Address, concurrency int) map[common.Address]walletState {
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	states := make(map[common.Address]walletState, len(addresses))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, concurrency)
+
+	for _, addr := range addresses {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(addr common.Address) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			balance, err := client.BalanceAt(context.Background(), addr, nil)
+			if err != nil {
+				return
+			}
+			nonce, err := client.PendingNonceAt(context.Background(), addr)
+			if err != nil {
+				return
+			}
+
+			mu.Lock()
+			states[addr] = walletState{Balance: balance, Nonce: nonce}
+			mu.Unlock()
+		}(addr)
+	}
+	wg.Wait()
+
+	return states
+}
@@ -0,0 +1,168 @@
+package cmd
+
+import (
+	"AccountSplitting/lib"
+	"context"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/spf13/cobra"
+)
+
+var (
+	prepareRPCURL        string
+	prepareCSVPath       string
+	prepareSenderCSVPath string
+	prepareSenderIndex   int
+	prepareAmount        string
+	prepareGasMultiplier float64
+	prepareGasLimit      uint64
+	prepareOutputPath    string
+	prepareStartNonce    int64
+)
+
+// PrepareCmd 是离线签名交易导出命令
+var PrepareCmd = &cobra.Command{
+	Use:   "prepare-txs",
+	Short: "为 CSV 中的每个接收者生成预签名的原始交易",
+	Long:  `读取接收者 CSV，一次性在线获取 nonce 和 gas 参数，为每个接收者签名一笔转账交易并写入文件，之后可在联网机器上使用 broadcast 命令广播。`,
+	Run: func(cmd *cobra.Command, args []string) {
+		// 验证参数
+		if prepareCSVPath == "" {
+			log.Fatal("请提供接收者钱包 CSV 文件路径 (--csv)")
+		}
+		amountWei, err := parseAmount(prepareAmount)
+		if err != nil {
+			log.Fatalf("转账金额无效 (--amount): %v", err)
+		}
+		if amountWei.Sign() <= 0 {
+			log.Fatal("转账金额必须大于 0 (--amount)")
+		}
+		if prepareSenderIndex < 0 {
+			log.Fatal("发送者钱包索引不能为负数 (--sender-index)")
+		}
+
+		// 读取接收者和发送者钱包信息
+		recipients, err := readWalletsFromCSV(prepareCSVPath)
+		if err != nil {
+			log.Fatalf("读取接收者钱包 CSV 文件失败: %v", err)
+		}
+		senderWallets, err := readWalletsFromCSV(prepareSenderCSVPath)
+		if err != nil {
+			log.Fatalf("读取发送者钱包 CSV 文件失败: %v", err)
+		}
+		if prepareSenderIndex >= len(senderWallets) {
+			log.Fatalf("发送者钱包索引超出范围 (0-%d)", len(senderWallets)-1)
+		}
+		privateKey, err := crypto.HexToECDSA(strings.TrimPrefix(senderWallets[prepareSenderIndex].PrivateKey, "0x"))
+		if err != nil {
+			log.Fatalf("解析发送者私钥失败: %v", err)
+		}
+		fromAddress := crypto.PubkeyToAddress(privateKey.PublicKey)
+
+		// 连接以太坊网络，一次性获取签名所需的链上参数
+		client, err := dialClient(context.Background(), prepareRPCURL)
+		if err != nil {
+			log.Fatalf("连接以太坊网络失败: %v", err)
+		}
+		chainID, err := client.ChainID(context.Background())
+		if err != nil {
+			log.Fatalf("获取链 ID 失败: %v", err)
+		}
+		nonces := lib.NewNonceManager(client, fromAddress)
+		if prepareStartNonce >= 0 {
+			nonces.Set(uint64(prepareStartNonce))
+		}
+		nonce, err := nonces.Peek(context.Background())
+		if err != nil {
+			log.Fatalf("获取 nonce 失败: %v", err)
+		}
+		suggestedGasPrice, err := client.SuggestGasPrice(context.Background())
+		if err != nil {
+			log.Fatalf("获取网络 gas 价格失败: %v", err)
+		}
+		gasPriceWei := new(big.Int).Mul(suggestedGasPrice, big.NewInt(int64(prepareGasMultiplier*10000)))
+		gasPriceWei = gasPriceWei.Div(gasPriceWei, big.NewInt(10000))
+
+		gasLimit := prepareGasLimit
+		if gasLimit == 0 {
+			firstRecipient := common.HexToAddress(recipients[0].Address)
+			estimatedGas, err := client.EstimateGas(context.Background(), ethereum.CallMsg{
+				From:  fromAddress,
+				To:    &firstRecipient,
+				Value: amountWei,
+			})
+			if err != nil {
+				log.Fatalf("估算 gas 失败: %v", err)
+			}
+			gasLimit = estimatedGas * 12 / 10 // 增加 20% 的缓冲
+		}
+
+		log.Printf("配置信息:")
+		log.Printf("- 发送者钱包: %s (索引: %d)", fromAddress.Hex(), prepareSenderIndex)
+		log.Printf("- 接收者数量: %d", len(recipients))
+		log.Printf("- 每个钱包转账金额: %s ETH", formatEther(amountWei))
+		log.Printf("- 链 ID: %s，起始 nonce: %d", chainID.String(), nonce)
+		log.Printf("- Gas 价格: %.1f Gwei，Gas 限制: %d", float64(gasPriceWei.Int64())/1e9, gasLimit)
+
+		// 逐个签名交易
+		signer := types.NewEIP155Signer(chainID)
+		var lines []string
+		for i, recipient := range recipients {
+			if !common.IsHexAddress(recipient.Address) {
+				log.Fatalf("第 %d 个接收者地址无效: %s", i+1, recipient.Address)
+			}
+			nonce, err := nonces.Next(context.Background())
+			if err != nil {
+				log.Fatalf("第 %d 笔交易获取 nonce 失败: %v", i+1, err)
+			}
+			tx := types.NewTransaction(nonce, common.HexToAddress(recipient.Address), amountWei, gasLimit, gasPriceWei, nil)
+			signedTx, err := types.SignTx(tx, signer, privateKey)
+			if err != nil {
+				log.Fatalf("第 %d 笔交易签名失败: %v", i+1, err)
+			}
+			raw, err := signedTx.MarshalBinary()
+			if err != nil {
+				log.Fatalf("第 %d 笔交易编码失败: %v", i+1, err)
+			}
+			lines = append(lines, hexutil.Encode(raw))
+		}
+
+		outputPath := prepareOutputPath
+		if outputPath == "" {
+			sourceFileName := filepath.Base(prepareCSVPath)
+			outputPath = fmt.Sprintf("results/%s_signed.txt", strings.TrimSuffix(sourceFileName, filepath.Ext(sourceFileName)))
+		}
+		if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
+			log.Fatalf("创建输出目录失败: %v", err)
+		}
+		if err := os.WriteFile(outputPath, []byte(strings.Join(lines, "\n")+"\n"), 0600); err != nil {
+			log.Fatalf("写入签名交易文件失败: %v", err)
+		}
+
+		log.Printf("已生成 %d 笔预签名交易，写入文件: %s", len(lines), outputPath)
+	},
+}
+
+func init() {
+	PrepareCmd.Flags().StringVar(&prepareRPCURL, "rpc", "https://bsc-dataseed.binance.org/", "以太坊 RPC URL")
+	PrepareCmd.Flags().StringVar(&prepareCSVPath, "csv", "", "接收者钱包 CSV 文件路径")
+	PrepareCmd.Flags().StringVar(&prepareSenderCSVPath, "sender-csv", "wallets/senders/w1.csv", "发送者钱包 CSV 文件路径")
+	PrepareCmd.Flags().IntVar(&prepareSenderIndex, "sender-index", 0, "发送者钱包在 CSV 中的索引")
+	PrepareCmd.Flags().StringVar(&prepareAmount, "amount", "0.1", "每个钱包转账金额 (ETH)，支持 1,000.5、1e-3、0.000_1 及 wei/gwei/ether 单位后缀")
+	PrepareCmd.Flags().Float64Var(&prepareGasMultiplier, "gas-multiplier", 1.0001, "Gas 价格倍率 (相对于网络平均 gas 价格)")
+	PrepareCmd.Flags().Uint64Var(&prepareGasLimit, "gas-limit", 0, "固定的 Gas 限制 (如果设置，将跳过估算)")
+	PrepareCmd.Flags().Int64Var(&prepareStartNonce, "start-nonce", -1, "起始 nonce (-1 表示自动获取)，之后每笔交易在本地递增")
+	PrepareCmd.Flags().StringVarP(&prepareOutputPath, "output", "o", "", "签名交易输出文件 (默认 results/<csv 文件名>_signed.txt)")
+
+	PrepareCmd.MarkFlagRequired("csv")
+}
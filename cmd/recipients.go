@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Recipient 分账接收者及其转账金额
+type Recipient struct {
+	Address common.Address
+	Amount  *big.Int // 以 Wei 为单位
+}
+
+// recipientsFromWallets 将钱包列表转换为接收者列表，未指定 Amount 列的钱包使用统一金额
+func recipientsFromWallets(wallets []WalletInfo, amount *big.Int) ([]Recipient, error) {
+	var recipients []Recipient
+	for i, wallet := range wallets {
+		if !common.IsHexAddress(wallet.Address) {
+			return nil, fmt.Errorf("第 %d 个接收者地址无效: %s", i+1, wallet.Address)
+		}
+		walletAmount := amount
+		if wallet.Amount != nil {
+			walletAmount = wallet.Amount
+		}
+		if walletAmount == nil || walletAmount.Sign() <= 0 {
+			return nil, fmt.Errorf("第 %d 个接收者 %s 没有有效的转账金额", i+1, wallet.Address)
+		}
+		recipients = append(recipients, Recipient{
+			Address: common.HexToAddress(wallet.Address),
+			Amount:  walletAmount,
+		})
+	}
+	return recipients, nil
+}
+
+// readRecipientsFromJSON 读取 [{"address": "0x...", "amount": "0.01"}] 格式的接收者列表，金额以 ETH 为单位
+func readRecipientsFromJSON(filePath string) ([]Recipient, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("打开 JSON 文件失败: %v", err)
+	}
+
+	var entries []struct {
+		Address string      `json:"address"`
+		Amount  json.Number `json:"amount"`
+	}
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("解析 JSON 文件失败: %v", err)
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("JSON 文件中没有接收者")
+	}
+
+	var recipients []Recipient
+	for i, entry := range entries {
+		address := strings.TrimSpace(entry.Address)
+		if !common.IsHexAddress(address) {
+			return nil, fmt.Errorf("第 %d 个接收者地址无效: %s", i+1, entry.Address)
+		}
+		amount, err := parseAmount(entry.Amount.String())
+		if err != nil {
+			return nil, fmt.Errorf("第 %d 个接收者金额无效: %v", i+1, err)
+		}
+		if amount.Sign() <= 0 {
+			return nil, fmt.Errorf("第 %d 个接收者金额必须大于 0", i+1)
+		}
+		recipients = append(recipients, Recipient{
+			Address: common.HexToAddress(address),
+			Amount:  amount,
+		})
+	}
+	return recipients, nil
+}
+
+// sumRecipientAmounts 计算接收者金额总和
+func sumRecipientAmounts(recipients []Recipient) *big.Int {
+	total := new(big.Int)
+	for _, recipient := range recipients {
+		total.Add(total, recipient.Amount)
+	}
+	return total
+}
+
+// SourceCount 记录每个接收者文件读取到的接收者数量
+type SourceCount struct {
+	Path  string
+	Count int
+}
+
+// readRecipientsFromCSVs 依次读取多个接收者 CSV 并合并，dedupe 为 true 时按地址去重（保留首次出现的记录），
+// 返回合并后的接收者、每个文件的数量以及被去重的数量
+func readRecipientsFromCSVs(paths []string, amount *big.Int, dedupe bool) ([]Recipient, []SourceCount, int, error) {
+	var recipients []Recipient
+	var counts []SourceCount
+	seen := make(map[common.Address]bool)
+	duplicates := 0
+	for _, path := range paths {
+		wallets, err := readWalletsFromCSV(path)
+		if err != nil {
+			return nil, nil, 0, fmt.Errorf("%s: %v", path, err)
+		}
+		fileRecipients, err := recipientsFromWallets(wallets, amount)
+		if err != nil {
+			return nil, nil, 0, fmt.Errorf("%s: %v", path, err)
+		}
+		counts = append(counts, SourceCount{Path: path, Count: len(fileRecipients)})
+		for _, recipient := range fileRecipients {
+			if dedupe {
+				if seen[recipient.Address] {
+					duplicates++
+					continue
+				}
+				seen[recipient.Address] = true
+			}
+			recipients = append(recipients, recipient)
+		}
+	}
+	return recipients, counts, duplicates, nil
+}
@@ -0,0 +1,209 @@
+package cmd
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"log"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/spf13/cobra"
+)
+
+var (
+	relayRPCURL            string
+	relayCSVPath           string
+	relayIntermediateCSV   string
+	relayIntermediateIndex int
+	relayTarget            string
+	relayGasMultiplier     float64
+	relayMaxWallets        int
+)
+
+// relayTransferGas 是普通转账的 gas 消耗
+const relayTransferGas = 21000
+
+// sendNative 由 privateKey 对应的钱包向 to 转入 amount 的原生币，返回已发送的交易
+func sendNative(client *ethclient.Client, chainID *big.Int, privateKey *ecdsa.PrivateKey, to common.Address, amount, gasPriceWei *big.Int) (*types.Transaction, error) {
+	ctx := context.Background()
+	from := crypto.PubkeyToAddress(privateKey.PublicKey)
+	nonce, err := client.PendingNonceAt(ctx, from)
+	if err != nil {
+		return nil, fmt.Errorf("获取 nonce 失败: %v", err)
+	}
+	tx := types.NewTransaction(nonce, to, amount, relayTransferGas, gasPriceWei, nil)
+	signedTx, err := types.SignTx(tx, types.NewEIP155Signer(chainID), privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("签名交易失败: %v", err)
+	}
+	if err := client.SendTransaction(ctx, signedTx); err != nil {
+		return nil, fmt.Errorf("发送交易失败: %v", err)
+	}
+	return signedTx, nil
+}
+
+// RelayCmd 是经由中间钱包归集并转出资金的命令
+var RelayCmd = &cobra.Command{
+	Use:   "relay",
+	Short: "将多个来源钱包归集到中间钱包，再整体转入最终目标地址",
+	Long:  `第一步把 CSV 中每个来源钱包的全部余额（扣除手续费）转入中间钱包，等待全部确认；第二步把中间钱包收到的总额扣除转出手续费后转入 --target。中间钱包原有的余额不会被转出，最后报告端到端到账的金额。`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if relayCSVPath == "" {
+			log.Fatal("请提供来源钱包 CSV 文件路径 (--csv)")
+		}
+		if !common.IsHexAddress(relayTarget) {
+			log.Fatalf("无效的目标地址 (--target): %s", relayTarget)
+		}
+		target := common.HexToAddress(relayTarget)
+
+		wallets, err := readWalletsFromCSV(relayCSVPath)
+		if err != nil {
+			log.Fatalf("读取来源钱包 CSV 文件失败: %v", err)
+		}
+		if relayMaxWallets > 0 && len(wallets) > relayMaxWallets {
+			wallets = wallets[:relayMaxWallets]
+		}
+		intermediateWallets, err := readWalletsFromCSV(relayIntermediateCSV)
+		if err != nil {
+			log.Fatalf("读取中间钱包 CSV 文件失败: %v", err)
+		}
+		if relayIntermediateIndex < 0 || relayIntermediateIndex >= len(intermediateWallets) {
+			log.Fatalf("中间钱包索引超出范围 (0-%d)", len(intermediateWallets)-1)
+		}
+		intermediateKey, err := crypto.HexToECDSA(strings.TrimPrefix(intermediateWallets[relayIntermediateIndex].PrivateKey, "0x"))
+		if err != nil {
+			log.Fatalf("解析中间钱包私钥失败: %v", err)
+		}
+		intermediate := crypto.PubkeyToAddress(intermediateKey.PublicKey)
+		if intermediate == target {
+			log.Fatal("中间钱包不能与目标地址相同")
+		}
+
+		ctx := context.Background()
+		client, err := dialClient(ctx, relayRPCURL)
+		if err != nil {
+			log.Fatalf("连接以太坊网络失败: %v", err)
+		}
+		chainID, err := client.ChainID(ctx)
+		if err != nil {
+			log.Fatalf("获取链 ID 失败: %v", err)
+		}
+		suggestedGasPrice, err := client.SuggestGasPrice(ctx)
+		if err != nil {
+			log.Fatalf("获取网络 gas 价格失败: %v", err)
+		}
+		gasPriceWei := new(big.Int).Mul(suggestedGasPrice, big.NewInt(int64(relayGasMultiplier*10000)))
+		gasPriceWei.Div(gasPriceWei, big.NewInt(10000))
+		fee := new(big.Int).Mul(gasPriceWei, big.NewInt(relayTransferGas))
+
+		log.Printf("配置信息:")
+		log.Printf("- 来源钱包数量: %d", len(wallets))
+		log.Printf("- 中间钱包: %s (索引: %d)", intermediate.Hex(), relayIntermediateIndex)
+		log.Printf("- 目标地址: %s", target.Hex())
+		log.Printf("- Gas 价格: %.4f Gwei，每笔手续费: %s", float64(gasPriceWei.Int64())/1e9, formatEther(fee))
+
+		// 第一步：来源钱包全部余额（扣除手续费）转入中间钱包
+		log.Printf("第一步: 归集到中间钱包")
+		var sent []*types.Transaction
+		var sentAmounts []*big.Int
+		for i, wallet := range wallets {
+			privateKey, err := crypto.HexToECDSA(strings.TrimPrefix(wallet.PrivateKey, "0x"))
+			if err != nil {
+				log.Printf("[%d/%d] %s 解析私钥失败，跳过: %v", i+1, len(wallets), wallet.Address, err)
+				continue
+			}
+			from := crypto.PubkeyToAddress(privateKey.PublicKey)
+			if from == intermediate {
+				log.Printf("[%d/%d] %s 就是中间钱包，跳过", i+1, len(wallets), from.Hex())
+				continue
+			}
+			balance, err := client.BalanceAt(ctx, from, nil)
+			if err != nil {
+				log.Printf("[%d/%d] %s 获取余额失败，跳过: %v", i+1, len(wallets), from.Hex(), err)
+				continue
+			}
+			if balance.Cmp(fee) <= 0 {
+				log.Printf("[%d/%d] %s 余额 %s 不足以支付手续费，跳过", i+1, len(wallets), from.Hex(), formatEther(balance))
+				continue
+			}
+			amount := new(big.Int).Sub(balance, fee)
+			tx, err := sendNative(client, chainID, privateKey, intermediate, amount, gasPriceWei)
+			if err != nil {
+				log.Printf("[%d/%d] %s %v", i+1, len(wallets), from.Hex(), err)
+				continue
+			}
+			log.Printf("[%d/%d] %s 转入中间钱包 %s，交易哈希: %s", i+1, len(wallets), from.Hex(), formatEther(amount), tx.Hash().Hex())
+			sent = append(sent, tx)
+			sentAmounts = append(sentAmounts, amount)
+		}
+		if len(sent) == 0 {
+			log.Fatal("没有可归集的来源钱包")
+		}
+
+		log.Printf("等待 %d 笔归集交易确认...", len(sent))
+		received := new(big.Int)
+		confirmed := 0
+		for i, tx := range sent {
+			receipt, err := bind.WaitMined(ctx, client, tx)
+			if err != nil {
+				log.Printf("等待交易 %s 确认失败: %v", tx.Hash().Hex(), err)
+				continue
+			}
+			if receipt.Status != types.ReceiptStatusSuccessful {
+				log.Printf("归集交易执行失败: %s", tx.Hash().Hex())
+				continue
+			}
+			received.Add(received, sentAmounts[i])
+			confirmed++
+		}
+		log.Printf("归集完成，%d 笔交易确认，中间钱包共收到 %s", confirmed, formatEther(received))
+
+		// 第二步：中间钱包把收到的总额扣除手续费后转入目标地址，原有余额保留
+		log.Printf("第二步: 由中间钱包转入目标地址")
+		if received.Cmp(fee) <= 0 {
+			log.Fatalf("中间钱包收到的金额 %s 不足以支付转出手续费 %s", formatEther(received), formatEther(fee))
+		}
+		forward := new(big.Int).Sub(received, fee)
+		intermediateBalance, err := client.BalanceAt(ctx, intermediate, nil)
+		if err != nil {
+			log.Fatalf("获取中间钱包余额失败: %v", err)
+		}
+		if intermediateBalance.Cmp(received) < 0 {
+			log.Fatalf("中间钱包余额 %s 低于收到的金额 %s，可能有其他交易正在使用该钱包，已中止", formatEther(intermediateBalance), formatEther(received))
+		}
+		tx, err := sendNative(client, chainID, intermediateKey, target, forward, gasPriceWei)
+		if err != nil {
+			log.Fatalf("中间钱包转出失败: %v", err)
+		}
+		log.Printf("中间钱包转出 %s，交易哈希: %s", formatEther(forward), tx.Hash().Hex())
+		receipt, err := bind.WaitMined(ctx, client, tx)
+		if err != nil {
+			log.Fatalf("等待转出交易确认失败: %v", err)
+		}
+		if receipt.Status != types.ReceiptStatusSuccessful {
+			log.Fatalf("转出交易执行失败: %s", tx.Hash().Hex())
+		}
+
+		totalFees := new(big.Int).Mul(fee, big.NewInt(int64(confirmed+1)))
+		log.Printf("\n中转完成！来源钱包 %d 个，端到端到账 %s，手续费合计 %s", confirmed, formatEther(forward), formatEther(totalFees))
+	},
+}
+
+func init() {
+	RelayCmd.Flags().StringVar(&relayRPCURL, "rpc", "https://bsc-dataseed.binance.org/", "以太坊 RPC URL")
+	RelayCmd.Flags().StringVar(&relayCSVPath, "csv", "", "来源钱包 CSV 文件路径")
+	RelayCmd.Flags().StringVar(&relayIntermediateCSV, "intermediate-csv", "wallets/senders/w1.csv", "中间钱包 CSV 文件路径")
+	RelayCmd.Flags().IntVar(&relayIntermediateIndex, "intermediate-index", 0, "中间钱包在 CSV 中的索引")
+	RelayCmd.Flags().StringVar(&relayTarget, "target", "", "最终目标地址")
+	RelayCmd.Flags().Float64Var(&relayGasMultiplier, "gas-multiplier", 1.0001, "Gas 价格倍率 (相对于网络平均 gas 价格)")
+	RelayCmd.Flags().IntVar(&relayMaxWallets, "max-wallets", 0, "最大处理来源钱包数量 (0 表示不限制)")
+
+	RelayCmd.MarkFlagRequired("csv")
+	RelayCmd.MarkFlagRequired("target")
+}
@@ -0,0 +1,212 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TransferResult 用于记录转账结果，CSV/JSON/JSONL 报告使用相同的字段
+type TransferResult struct {
+	Address   string `json:"address"` // 来源地址
+	Target    string `json:"target"`
+	Amount    string `json:"amount"` // 以 Wei 为单位
+	TxHash    string `json:"txhash"`
+	GasUsed   uint64 `json:"gas"`
+	IsSuccess bool   `json:"success"`
+	Error     string `json:"error"`
+	RunID     string `json:"run_id"` // 产生该结果的运行 ID
+	State     string `json:"state"`  // 交易最终状态: mined, pending, replaced, dropped, unknown（未发送时为空）
+}
+
+// validateReportFormat 校验报告格式参数
+func validateReportFormat(format string) error {
+	switch format {
+	case "csv", "json", "jsonl":
+		return nil
+	}
+	return fmt.Errorf("不支持的报告格式: %s (可选 csv, json, jsonl)", format)
+}
+
+// resultFilePath 根据来源 CSV 生成报告文件路径，例如 results/k2_res.csv
+func resultFilePath(sourceCSVPath, suffix, format string) string {
+	sourceFileName := filepath.Base(sourceCSVPath)
+	if sourceCSVPath == stdinPath {
+		sourceFileName = "stdin"
+	}
+	return fmt.Sprintf("results/%s%s.%s", strings.TrimSuffix(sourceFileName, filepath.Ext(sourceFileName)), suffix, format)
+}
+
+// appendResult 将单条转账结果按指定格式追加到报告文件
+func appendResult(result TransferResult, outputFileName, format string) error {
+	result.RunID = RunID
+
+	// 创建 results 目录（如果不存在）
+	if err := os.MkdirAll(filepath.Dir(outputFileName), 0755); err != nil {
+		return fmt.Errorf("创建 results 目录失败: %v", err)
+	}
+
+	switch format {
+	case "json":
+		return appendResultToJSON(result, outputFileName)
+	case "jsonl":
+		return appendResultToJSONL(result, outputFileName)
+	default:
+		return appendResultToCSV(result, outputFileName)
+	}
+}
+
+// transferResultCSVHeader 是 CSV 报告的表头，列顺序与 appendResultToCSV 写入的记录一致
+var transferResultCSVHeader = []string{"address", "target", "amount", "txhash", "gas", "转账是否成功", "error", "run_id", "state"}
+
+// appendResultToCSV 将单条转账结果追加到 CSV 文件。
+// 已有文件的表头与当前列不一致时（旧版本生成的报告），先把旧文件改名保留，再写入新文件
+func appendResultToCSV(result TransferResult, outputFileName string) error {
+	writeHeader, err := checkCSVReportHeader(outputFileName)
+	if err != nil {
+		return err
+	}
+
+	// 打开文件（如果不存在则创建）
+	file, err := os.OpenFile(outputFileName, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("打开结果文件失败: %v", err)
+	}
+	defer file.Close()
+
+	// 创建 CSV writer
+	writer := csv.NewWriter(file)
+	defer writer.Flush()
+
+	// 如果文件是新创建的，写入表头
+	if writeHeader {
+		if err := writer.Write(transferResultCSVHeader); err != nil {
+			return fmt.Errorf("写入表头失败: %v", err)
+		}
+	}
+
+	// 写入单条数据
+	success := "是"
+	if !result.IsSuccess {
+		success = "否"
+	}
+	record := []string{
+		result.Address,
+		result.Target,
+		result.Amount,
+		result.TxHash,
+		strconv.FormatUint(result.GasUsed, 10),
+		success,
+		result.Error,
+		result.RunID,
+		result.State,
+	}
+	if err := writer.Write(record); err != nil {
+		return fmt.Errorf("写入数据失败: %v", err)
+	}
+
+	return nil
+}
+
+// checkCSVReportHeader 检查已有 CSV 报告的表头，返回是否需要写入表头。
+// 表头不一致时把旧文件改名为 <name>_old_<时间>.csv，避免新旧列数不同的记录混在同一个文件中
+func checkCSVReportHeader(outputFileName string) (bool, error) {
+	file, err := os.Open(outputFileName)
+	if os.IsNotExist(err) {
+		return true, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("打开结果文件失败: %v", err)
+	}
+	header, err := csv.NewReader(file).Read()
+	file.Close()
+	if err == io.EOF {
+		return true, nil
+	}
+	if err == nil && slices.Equal(header, transferResultCSVHeader) {
+		return false, nil
+	}
+
+	ext := filepath.Ext(outputFileName)
+	rotated := fmt.Sprintf("%s_old_%s%s", strings.TrimSuffix(outputFileName, ext), time.Now().Format("20060102150405"), ext)
+	if err := os.Rename(outputFileName, rotated); err != nil {
+		return false, fmt.Errorf("结果文件 %s 的表头与当前版本不一致，改名保留旧文件失败: %v", outputFileName, err)
+	}
+	log.Printf("结果文件 %s 的表头与当前版本不一致，旧文件已改名为 %s", outputFileName, rotated)
+	return true, nil
+}
+
+// appendResultToJSONL 将单条转账结果以 JSON Lines 格式追加到文件
+func appendResultToJSONL(result TransferResult, outputFileName string) error {
+	file, err := os.OpenFile(outputFileName, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("打开结果文件失败: %v", err)
+	}
+	defer file.Close()
+
+	if err := json.NewEncoder(file).Encode(result); err != nil {
+		return fmt.Errorf("写入数据失败: %v", err)
+	}
+	return nil
+}
+
+// appendResultToJSON 将单条转账结果追加到 JSON 数组文件：只覆盖末尾的 "]"，写入新元素后重新闭合数组，
+// 每条结果的写入量与文件大小无关，且每次写入后文件都是合法 JSON
+func appendResultToJSON(result TransferResult, outputFileName string) error {
+	data, err := json.MarshalIndent(result, "  ", "  ")
+	if err != nil {
+		return fmt.Errorf("JSON 编码失败: %v", err)
+	}
+
+	file, err := os.OpenFile(outputFileName, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return fmt.Errorf("打开结果文件失败: %v", err)
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("读取结果文件信息失败: %v", err)
+	}
+
+	offset, prefix := int64(0), "[\n  "
+	if info.Size() > 0 {
+		offset, prefix, err = jsonArrayAppendPoint(file, info.Size())
+		if err != nil {
+			return fmt.Errorf("解析已有结果文件失败: %v", err)
+		}
+	}
+	if _, err := file.WriteAt([]byte(prefix+string(data)+"\n]\n"), offset); err != nil {
+		return fmt.Errorf("写入数据失败: %v", err)
+	}
+	return nil
+}
+
+// jsonArrayAppendPoint 读取 JSON 数组文件的末尾，返回结尾 "]" 的位置以及新元素前需要写入的分隔符
+func jsonArrayAppendPoint(file *os.File, size int64) (int64, string, error) {
+	start := max(size-256, 0)
+	tail := make([]byte, size-start)
+	if _, err := file.ReadAt(tail, start); err != nil {
+		return 0, "", err
+	}
+	trimmed := strings.TrimRight(string(tail), " \t\r\n")
+	if !strings.HasSuffix(trimmed, "]") {
+		return 0, "", fmt.Errorf("文件不是以 ] 结尾的 JSON 数组")
+	}
+	closing := start + int64(len(trimmed)) - 1
+	before := strings.TrimRight(trimmed[:len(trimmed)-1], " \t\r\n")
+	switch {
+	case strings.HasSuffix(before, "["):
+		return closing, "\n  ", nil
+	case strings.HasSuffix(before, "}"):
+		return closing, ",\n  ", nil
+	}
+	return 0, "", fmt.Errorf("文件不是对象组成的 JSON 数组")
+}
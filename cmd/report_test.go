@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestAppendResultToJSON(t *testing.T) {
+	for _, initial := range []string{"", "[]\n", "[\n]"} {
+		path := filepath.Join(t.TempDir(), "res.json")
+		if initial != "" {
+			if err := os.WriteFile(path, []byte(initial), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		for i, address := range []string{"0x1", "0x2", "0x3"} {
+			if err := appendResultToJSON(TransferResult{Address: address, IsSuccess: i%2 == 0}, path); err != nil {
+				t.Fatalf("initial %q, append %d: %v", initial, i, err)
+			}
+
+			// 每次追加后文件都应是合法的 JSON 数组
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var results []TransferResult
+			if err := json.Unmarshal(data, &results); err != nil {
+				t.Fatalf("initial %q, after %d appends invalid JSON: %v\n%s", initial, i+1, err, data)
+			}
+			if len(results) != i+1 || results[i].Address != address {
+				t.Fatalf("initial %q, after %d appends got %+v", initial, i+1, results)
+			}
+		}
+	}
+}
+
+func TestAppendResultToJSONRejectsNonArray(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "res.json")
+	if err := os.WriteFile(path, []byte(`{"address":"0x1"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := appendResultToJSON(TransferResult{Address: "0x2"}, path); err == nil {
+		t.Fatal("appending to a JSON object should fail")
+	}
+}
+
+func TestAppendResultToCSVRotatesOldHeader(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "res.csv")
+	if err := os.WriteFile(path, []byte("address,txhash,转账是否成功\n0x1,0xabc,是\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for _, address := range []string{"0x2", "0x3"} {
+		if err := appendResultToCSV(TransferResult{Address: address, IsSuccess: true}, path); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	records, err := csv.NewReader(file).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 3 || !slices.Equal(records[0], transferResultCSVHeader) || records[2][0] != "0x3" {
+		t.Fatalf("got %v", records)
+	}
+
+	rotated, err := filepath.Glob(filepath.Join(dir, "res_old_*.csv"))
+	if err != nil || len(rotated) != 1 {
+		t.Fatalf("old report should be kept, got %v (%v)", rotated, err)
+	}
+}
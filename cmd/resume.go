@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// resumeIndexFromTx 解码已确认批次交易的调用数据，在接收者列表中找到该批次覆盖的连续区间，
+// 返回下一个未覆盖接收者的下标
+func resumeIndexFromTx(client *ethclient.Client, parsedABI abi.ABI, method string, contract common.Address, txHash string, wallets []Recipient) (int, error) {
+	hash := common.HexToHash(txHash)
+	tx, isPending, err := client.TransactionByHash(context.Background(), hash)
+	if err != nil {
+		return 0, fmt.Errorf("查询交易 %s 失败: %v", txHash, err)
+	}
+	if isPending {
+		return 0, fmt.Errorf("交易 %s 尚未确认", txHash)
+	}
+	receipt, err := client.TransactionReceipt(context.Background(), hash)
+	if err != nil {
+		return 0, fmt.Errorf("查询交易 %s 回执失败: %v", txHash, err)
+	}
+	if receipt.Status == 0 {
+		return 0, fmt.Errorf("交易 %s 执行失败，不能作为恢复起点", txHash)
+	}
+	if tx.To() == nil || *tx.To() != contract {
+		return 0, fmt.Errorf("交易 %s 不是发往合约 %s 的交易", txHash, contract.Hex())
+	}
+
+	// 解码调用数据中的接收者列表
+	m := parsedABI.Methods[method]
+	data := tx.Data()
+	if len(data) < 4 || !bytes.Equal(data[:4], m.ID) {
+		return 0, fmt.Errorf("交易 %s 不是 %s 调用", txHash, method)
+	}
+	args, err := m.Inputs.Unpack(data[4:])
+	if err != nil {
+		return 0, fmt.Errorf("解码交易 %s 的调用数据失败: %v", txHash, err)
+	}
+	covered, ok := args[0].([]common.Address)
+	if !ok || len(covered) == 0 {
+		return 0, fmt.Errorf("交易 %s 中没有接收者", txHash)
+	}
+
+	// 在接收者列表中查找与该批次完全一致的连续区间
+	for start := 0; start+len(covered) <= len(wallets); start++ {
+		matched := true
+		for i, addr := range covered {
+			if wallets[start+i].Address != addr {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return start + len(covered), nil
+		}
+	}
+	return 0, fmt.Errorf("交易 %s 中的 %d 个接收者与接收者列表不匹配", txHash, len(covered))
+}
@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// retryableErrorPatterns 是内置的可重试错误特征（不区分大小写），通常由 RPC 节点的临时故障引起。
+// 状态码和 EOF 按完整单词匹配，避免交易哈希、地址或金额中恰好包含 "503" 等数字时被误判为可重试
+var retryableErrorPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`timeout`),
+	regexp.MustCompile(`deadline exceeded`),
+	regexp.MustCompile(`connection reset`),
+	regexp.MustCompile(`connection refused`),
+	regexp.MustCompile(`broken pipe`),
+	regexp.MustCompile(`\beof\b`),
+	regexp.MustCompile(`too many requests`),
+	regexp.MustCompile(`rate limit`),
+	regexp.MustCompile(`\b(429|502|503|504)\b`),
+	regexp.MustCompile(`header not found`),
+	regexp.MustCompile(`temporarily unavailable`),
+}
+
+// RetryableErrors 是用户通过 --retryable-errors 追加的可重试错误特征，与内置特征一起匹配
+var RetryableErrors []string
+
+// errRetryBudgetExhausted 表示整个运行共享的重试次数已经用完
+var errRetryBudgetExhausted = errors.New("全局重试次数已用完 (--max-total-retries)，节点可能持续不可用，已中止运行")
+
+// isRetryableError 判断错误是否为可重试的临时错误
+func isRetryableError(err error) bool {
+	msg := strings.ToLower(err.Error())
+	for _, pattern := range retryableErrorPatterns {
+		if pattern.MatchString(msg) {
+			return true
+		}
+	}
+	for _, pattern := range RetryableErrors {
+		pattern = strings.ToLower(strings.TrimSpace(pattern))
+		if pattern != "" && strings.Contains(msg, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// retryBudget 是整个运行中所有批次/钱包共享的重试次数预算，max 为 0 表示不限制
+type retryBudget struct {
+	max       int
+	used      int
+	exhausted bool // 曾有重试因预算用完被拒绝
+}
+
+func newRetryBudget(max int) *retryBudget {
+	return &retryBudget{max: max}
+}
+
+// take 消耗一次重试，预算用完时返回 false
+func (b *retryBudget) take() bool {
+	if b.max > 0 && b.used >= b.max {
+		b.exhausted = true
+		return false
+	}
+	b.used++
+	return true
+}
+
+// withRetries 执行 fn，遇到可重试错误时最多重试 retries 次，每次重试消耗一次全局预算，重试间隔逐次增加 1 秒。
+// ctx 结束后不再重试；全局预算用完时返回包装了 errRetryBudgetExhausted 的错误，调用方应中止整个运行
+func withRetries(ctx context.Context, label string, retries int, budget *retryBudget, fn func() error) error {
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil || attempt > retries || ctx.Err() != nil || !isRetryableError(err) {
+			return err
+		}
+		if !budget.take() {
+			return fmt.Errorf("%w，最后一次错误: %v", errRetryBudgetExhausted, err)
+		}
+		log.Printf("%s失败: %v，%d 秒后进行第 %d/%d 次重试（全局已重试 %d 次）", label, err, attempt, attempt, retries, budget.used)
+		select {
+		case <-time.After(time.Duration(attempt) * time.Second):
+		case <-ctx.Done():
+			return err
+		}
+	}
+}
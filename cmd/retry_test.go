@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestIsRetryableError(t *testing.T) {
+	tests := []struct {
+		msg  string
+		want bool
+	}{
+		{"429 Too Many Requests", true},
+		{"502 Bad Gateway", true},
+		{"server returned status 503", true},
+		{"unexpected EOF", true},
+		{"EOF", true},
+		{"Post \"https://rpc\": context deadline exceeded", true},
+		{"nonce too low", false},
+		{"insufficient funds for gas * price + value: balance 1503000", false},
+		{"execution reverted: tx 0xab429cd already known", false},
+		{"invalid geofence", false},
+	}
+	for _, tt := range tests {
+		if got := isRetryableError(errors.New(tt.msg)); got != tt.want {
+			t.Errorf("isRetryableError(%q) = %v, want %v", tt.msg, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// decodeRevertReason 在指定区块重放调用，尝试解析 Error(string) / Panic(uint256) 回滚原因
+func decodeRevertReason(client *ethclient.Client, msg ethereum.CallMsg, blockNumber *big.Int) string {
+	_, err := client.CallContract(context.Background(), msg, blockNumber)
+	if err == nil {
+		return "重放调用未回滚，可能是 gas 不足或状态已变化"
+	}
+	return revertReasonFromError(err)
+}
+
+// isRevertError 判断错误是否由调用回滚引起（而不是网络或节点错误）
+func isRevertError(err error) bool {
+	var dataErr rpc.DataError
+	if errors.As(err, &dataErr) {
+		return true
+	}
+	return strings.Contains(strings.ToLower(err.Error()), "revert")
+}
+
+// revertReasonFromError 从 RPC 错误中提取并解码回滚数据
+func revertReasonFromError(err error) string {
+	var dataErr rpc.DataError
+	if !errors.As(err, &dataErr) {
+		return err.Error()
+	}
+	hexData, ok := dataErr.ErrorData().(string)
+	if !ok {
+		return err.Error()
+	}
+	data, decodeErr := hexutil.Decode(hexData)
+	if decodeErr != nil {
+		return err.Error()
+	}
+	reason, unpackErr := abi.UnpackRevert(data)
+	if unpackErr != nil {
+		return fmt.Sprintf("%v (回滚数据: %s)", err, hexData)
+	}
+	return reason
+}
@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// RPCHeaders 是附加到每个 RPC 请求的自定义 HTTP 头，格式为 "Key: Value"
+var RPCHeaders []string
+
+// dialClient 连接 RPC 节点，并附加 --rpc-header 指定的请求头（如 API Key、User-Agent）
+func dialClient(ctx context.Context, url string) (*ethclient.Client, error) {
+	var opts []rpc.ClientOption
+	for _, header := range RPCHeaders {
+		key, value, ok := strings.Cut(header, ":")
+		if !ok || strings.TrimSpace(key) == "" {
+			return nil, fmt.Errorf("无效的请求头 %q，格式应为 \"Key: Value\"", header)
+		}
+		opts = append(opts, rpc.WithHeader(strings.TrimSpace(key), strings.TrimSpace(value)))
+	}
+
+	client, err := rpc.DialOptions(ctx, url, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return ethclient.NewClient(client), nil
+}
@@ -0,0 +1,18 @@
+package cmd
+
+import (
+	"log"
+
+	"github.com/google/uuid"
+)
+
+// RunID 标识一次命令执行，写入日志前缀和转账报告，便于关联同一次运行产生的所有文件
+var RunID string
+
+// SetupRunID 在未指定 --run-id 时自动生成 UUID，并将其设置为日志前缀
+func SetupRunID() {
+	if RunID == "" {
+		RunID = uuid.NewString()
+	}
+	log.SetPrefix("[" + RunID + "] ")
+}
@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// maxSaneBatchSize 是批次大小的上限，更大的批次几乎一定超过区块 gas 上限
+const maxSaneBatchSize = 2000
+
+// parseMaxTotalValue 解析 --max-total-value，为空时返回 nil（不限制）
+func parseMaxTotalValue(value string) (*big.Int, error) {
+	if value == "" {
+		return nil, nil
+	}
+	limit, err := parseAmount(value)
+	if err != nil {
+		return nil, fmt.Errorf("总金额上限无效 (--max-total-value): %v", err)
+	}
+	if limit.Sign() <= 0 {
+		return nil, fmt.Errorf("总金额上限必须大于 0 (--max-total-value)")
+	}
+	return limit, nil
+}
+
+// checkRecipientSanity 在连接节点前拦截明显错误的输入：接收者为空，或总金额超过 --max-total-value（通常是金额多打了一个 0）
+func checkRecipientSanity(count int, total, limit *big.Int) error {
+	if count <= 0 {
+		return fmt.Errorf("接收者列表为空，请检查 CSV 文件")
+	}
+	if limit != nil && total.Cmp(limit) > 0 {
+		return fmt.Errorf("总转账金额 %s 超过上限 %s（%d 个钱包），请检查 --amount 是否多写了 0，确认无误请调高 --max-total-value",
+			formatEther(total), formatEther(limit), count)
+	}
+	return nil
+}
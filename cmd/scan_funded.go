@@ -0,0 +1,192 @@
+package cmd
+
+import (
+	"AccountSplitting/lib"
+	"bufio"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/spf13/cobra"
+)
+
+var (
+	scanInputPath   string
+	scanRPCURL      string
+	scanOutputPath  string
+	scanIndexes     int
+	scanConcurrency int
+	scanMinBalance  string
+)
+
+// scanCandidate 是从助记词或私钥派生出的待检查地址
+type scanCandidate struct {
+	Address    common.Address
+	PrivateKey string
+	Mnemonic   string
+	Balance    *big.Int
+}
+
+// readScanCandidates 读取候选文件，每行一个助记词或十六进制私钥，空行和 # 开头的行被忽略。
+// 每个助记词派生前 indexes 个地址；无效的私钥或助记词记录日志后跳过，返回跳过的行数
+func readScanCandidates(path string, indexes int) ([]scanCandidate, int, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, 0, fmt.Errorf("打开候选文件失败: %v", err)
+	}
+	defer file.Close()
+
+	var candidates []scanCandidate
+	scanner := bufio.NewScanner(file)
+	lineNum := 0
+	skipped := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		// 只有一个词的行按私钥处理，否则按助记词处理
+		if len(strings.Fields(line)) == 1 {
+			key, err := crypto.HexToECDSA(strings.TrimPrefix(line, "0x"))
+			if err != nil {
+				log.Printf("第 %d 行私钥无效，已跳过: %v", lineNum, err)
+				skipped++
+				continue
+			}
+			candidates = append(candidates, scanCandidate{
+				Address:    crypto.PubkeyToAddress(key.PublicKey),
+				PrivateKey: fmt.Sprintf("%x", crypto.FromECDSA(key)),
+			})
+			continue
+		}
+
+		mnemonic := strings.Join(strings.Fields(line), " ")
+		addresses, privateKeys, err := lib.DeriveKeys(mnemonic, 0, uint32(indexes))
+		if err != nil {
+			log.Printf("第 %d 行助记词派生失败，已跳过: %v", lineNum, err)
+			skipped++
+			continue
+		}
+		for i, address := range addresses {
+			candidates = append(candidates, scanCandidate{Address: address, PrivateKey: privateKeys[i], Mnemonic: mnemonic})
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, 0, fmt.Errorf("读取候选文件失败: %v", err)
+	}
+	return candidates, skipped, nil
+}
+
+// ScanFundedCmd 是从候选助记词或私钥中找出有余额钱包的命令
+var ScanFundedCmd = &cobra.Command{
+	Use:   "scan-funded",
+	Short: "从候选助记词/私钥中找出链上有余额的钱包",
+	Long:  `读取候选文件（每行一个助记词或私钥），派生地址并并发查询链上余额，只把余额大于阈值的钱包写入输出 CSV，用于从大量恢复出的种子中找出实际持有资金的钱包。`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if scanInputPath == "" {
+			log.Fatal("请提供候选文件路径 (--input)")
+		}
+		if scanIndexes <= 0 {
+			log.Fatal("每个助记词派生的地址数量必须大于 0 (--indexes)")
+		}
+		if scanConcurrency <= 0 {
+			log.Fatal("并发数必须大于 0 (--concurrency)")
+		}
+		minBalance, err := parseAmount(scanMinBalance)
+		if err != nil {
+			log.Fatalf("无效的余额阈值 (--min-balance): %v", err)
+		}
+
+		candidates, skipped, err := readScanCandidates(scanInputPath, scanIndexes)
+		if err != nil {
+			log.Fatal(err)
+		}
+		log.Printf("共读取 %d 个候选地址，开始查询余额（并发数 %d）", len(candidates), scanConcurrency)
+
+		client, err := dialClient(context.Background(), scanRPCURL)
+		if err != nil {
+			log.Fatalf("连接以太坊网络失败: %v", err)
+		}
+
+		// 并发查询余额，查询失败的地址单独统计
+		progress := lib.NewProgress(len(candidates))
+		var mu sync.Mutex
+		var wg sync.WaitGroup
+		sem := make(chan struct{}, scanConcurrency)
+		failed := 0
+		for i := range candidates {
+			wg.Add(1)
+			sem <- struct{}{}
+			go func(c *scanCandidate) {
+				defer wg.Done()
+				defer func() { <-sem }()
+
+				balance, err := client.BalanceAt(context.Background(), c.Address, nil)
+				mu.Lock()
+				defer mu.Unlock()
+				if err != nil {
+					failed++
+				} else {
+					c.Balance = balance
+				}
+				progress.Add(1)
+			}(&candidates[i])
+		}
+		wg.Wait()
+		progress.Finish()
+
+		if err := os.MkdirAll(filepath.Dir(scanOutputPath), 0755); err != nil {
+			log.Fatalf("创建输出目录失败: %v", err)
+		}
+		file, err := os.OpenFile(scanOutputPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+		if err != nil {
+			log.Fatalf("创建输出文件失败: %v", err)
+		}
+		defer file.Close()
+		writer := csv.NewWriter(file)
+		writer.Write([]string{"Address", "Private Key", "Mnemonic", "Balance"})
+
+		funded := 0
+		total := new(big.Int)
+		for _, c := range candidates {
+			if c.Balance == nil || c.Balance.Sign() == 0 || c.Balance.Cmp(minBalance) < 0 {
+				continue
+			}
+			writer.Write([]string{c.Address.Hex(), c.PrivateKey, c.Mnemonic, formatEther(c.Balance)})
+			funded++
+			total.Add(total, c.Balance)
+		}
+		writer.Flush()
+		if err := writer.Error(); err != nil {
+			log.Fatalf("写入输出文件失败: %v", err)
+		}
+
+		log.Printf("扫描完成！有余额的钱包: %d，余额合计: %s", funded, formatEther(total))
+		if failed > 0 {
+			log.Printf("有 %d 个地址查询余额失败，未包含在结果中", failed)
+		}
+		if skipped > 0 {
+			log.Printf("有 %d 行私钥或助记词无效，已跳过", skipped)
+		}
+		log.Printf("结果已写入 %s", scanOutputPath)
+	},
+}
+
+func init() {
+	ScanFundedCmd.Flags().StringVar(&scanInputPath, "input", "", "候选文件路径，每行一个助记词或十六进制私钥")
+	ScanFundedCmd.Flags().StringVar(&scanRPCURL, "rpc", "https://bsc-dataseed.binance.org/", "以太坊 RPC URL")
+	ScanFundedCmd.Flags().StringVarP(&scanOutputPath, "output", "o", "results/funded_wallets.csv", "有余额钱包的输出 CSV 文件路径")
+	ScanFundedCmd.Flags().IntVar(&scanIndexes, "indexes", 1, "每个助记词派生并检查的地址数量 (m/44'/60'/0'/0/0 起)")
+	ScanFundedCmd.Flags().IntVar(&scanConcurrency, "concurrency", 10, "查询余额的最大并发请求数")
+	ScanFundedCmd.Flags().StringVar(&scanMinBalance, "min-balance", "0", "只输出余额不低于该值的钱包（余额为 0 的钱包始终跳过）")
+}
@@ -1,337 +1,978 @@
 package cmd
 
 import (
+	"AccountSplitting/lib"
+	"bufio"
 	"context"
-	"encoding/csv"
+	"errors"
 	"fmt"
 	"log"
 	"math/big"
 	"os"
-	"path/filepath"
 	"strings"
 	"time"
 
 	"github.com/ethereum/go-ethereum"
-	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
-	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/spf13/cobra"
 )
 
 var (
-	singleTransferRPCURL        string
-	singleTransferCSVPath       string
-	singleTransferTargetAddr    string
-	singleTransferAmount        float64
-	singleTransferGasMultiplier float64
-	singleTransferGasLimit      uint64
-	singleTransferMaxWallets    int
-	singleTransferDelay         int // 每次转账之间的延迟（秒）
+	singleTransferRPCURL              string
+	singleTransferCSVPath             string
+	singleTransferMnemonic            string // 直接从助记词派生来源钱包（替代 --csv）
+	singleTransferIndex               int    // 从助记词派生的地址索引
+	singleTransferTargetAddr          string
+	singleTransferTargets             string // 多个目标地址（逗号分隔），按轮询方式分配
+	singleTransferTargetCSV           string // 目标地址 CSV，与来源钱包按行一对一配对
+	singleTransferAmount              string
+	singleTransferAmountUSD           string  // 以美元计的每个钱包转账金额
+	singleTransferMaxTotalValue       string  // 总转账金额上限，超过时拒绝运行
+	singleTransferMaxGasTotal         string  // 手续费总额上限，超出时中止
+	singleTransferGasBuffer           int     // 估算 gas 后增加的缓冲百分比
+	singleTransferAbortOnInsufficient bool    // 遇到余额不足的钱包时中止全部转账
+	singleTransferPriceURL            string  // 原生币美元价格接口
+	singleTransferPricePath           string  // 价格在接口 JSON 中的字段路径
+	singleTransferPriceFallback       float64 // 价格接口不可用时的备用价格 (USD)
+	singleTransferGasMultiplier       float64
+	singleTransferGasPrice            float64 // 固定 gas 价格 (Gwei)，大于 0 时替代倍率
+	singleTransferGasOracleURL        string  // 外部 gas 价格接口
+	singleTransferMaxFeeGwei          float64 // EIP-1559 最高费用 (Gwei)
+	singleTransferMaxPriorityGwei     float64 // EIP-1559 优先费 (Gwei)
+	singleTransferGasOraclePath       string  // gas 价格在接口 JSON 中的字段路径
+	singleTransferGasLimit            uint64
+	singleTransferMaxWallets          int
+	singleTransferSkipFunded          bool          // 跳过目标余额已达到转账金额的钱包
+	singleTransferDelay               int           // 每次转账之间的延迟（秒）
+	singleTransferDelayJitter         string        // 延迟抖动（百分比或秒数）
+	singleTransferSeed                int64         // 延迟抖动随机数种子（0 表示随机）
+	singleTransferWaitTimeout         time.Duration // 等待交易确认的超时时间（0 表示一直等待）
+	singleTransferPerWalletTimeout    time.Duration // 每个钱包估算、发送和等待确认的总时间上限（0 表示不限制）
+	singleTransferEstimateEach        bool          // 每个钱包单独估算 gas（目标为合约时使用）
+	singleTransferConfirmEach         bool          // 每笔转账发送前逐一确认
+	singleTransferAutoRPC             bool
+	singleTransferRPCNodesFile        string // --auto-rpc 探测的节点文件
+	singleTransferExpectChainID       int64
+	singleTransferReportFormat        string // 转账报告格式 (csv, json, jsonl)
+	singleTransferGasBumpPercent      int    // 交易替换失败时重试的 gas 价格提高百分比
+	singleTransferRetries             int    // 每个钱包遇到临时 RPC 错误时的最大重试次数
+	singleTransferMaxTotalRetries     int    // 整个运行共享的重试次数上限
+	singleTransferOnlyFailures        bool   // 只输出失败的钱包和最终汇总
+	singleTransferEstimateOnly        bool   // 只估算并输出计划，不广播交易
+	singleTransferPrefetch            bool   // 发送前并发预取所有钱包的余额和 nonce
+	singleTransferVerifyAfter         bool   // 全部转账完成后核对目标地址余额增加量
+	singleTransferDumpRaw             string // 已签名交易十六进制的输出文件
+	singleTransferAllowContract       bool   // 允许目标地址为合约
+	singleTransferNoEIP155            bool   // 使用不带重放保护的 Homestead 签名
+	singleTransferData                string // 交易 data 字段的十六进制内容
+	singleTransferSymbol              string // 日志中金额的单位符号
+	singleTransferDecimals            int    // 日志中金额的小数位数
+	singleTransferPrefetchConcurrency int    // 预取时的最大并发请求数
 )
 
-// TransferResult 用于记录转账结果
-type TransferResult struct {
-	Address   string
-	TxHash    string
-	IsSuccess bool
+// walletContext 返回处理单个钱包使用的上下文，timeout 为 0 时不设超时
+func walletContext(timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout > 0 {
+		return context.WithTimeout(context.Background(), timeout)
+	}
+	return context.WithCancel(context.Background())
 }
 
-// appendResultToCSV 将单条转账结果追加到 CSV 文件
-func appendResultToCSV(result TransferResult, sourceCSVPath string) error {
-	// 创建 results 目录（如果不存在）
-	if err := os.MkdirAll("results", 0755); err != nil {
-		return fmt.Errorf("创建 results 目录失败: %v", err)
+// confirmTransfer 在发送前提示用户确认，返回 "send"、"skip" 或 "abort"
+func confirmTransfer(reader *bufio.Reader, from, to common.Address, amountWei *big.Int, unit DisplayUnit, gasLimit uint64, gasPriceWei *big.Int) string {
+	fee := new(big.Int).Mul(new(big.Int).SetUint64(gasLimit), gasPriceWei)
+	fmt.Printf("\n即将发送转账:\n")
+	fmt.Printf("  来源地址: %s\n", from.Hex())
+	fmt.Printf("  目标地址: %s\n", to.Hex())
+	fmt.Printf("  转账金额: %s\n", unit.Format(amountWei))
+	fmt.Printf("  Gas 限制: %d，Gas 价格: %.4f Gwei，最高手续费: %.8f BNB\n",
+		gasLimit, float64(gasPriceWei.Int64())/1e9, weiToEther(fee))
+	for {
+		fmt.Print("确认发送? [y]发送 / [s]跳过 / [a]中止全部: ")
+		input, err := reader.ReadString('\n')
+		if err != nil {
+			return "abort"
+		}
+		switch strings.ToLower(strings.TrimSpace(input)) {
+		case "y", "yes":
+			return "send"
+		case "s", "skip":
+			return "skip"
+		case "a", "abort":
+			return "abort"
+		}
 	}
+}
 
-	// 生成输出文件名
-	sourceFileName := filepath.Base(sourceCSVPath)
-	outputFileName := fmt.Sprintf("results/%s_res.csv", strings.TrimSuffix(sourceFileName, filepath.Ext(sourceFileName)))
+// SingleTransferConfig 逐个钱包转账的配置
+type SingleTransferConfig struct {
+	RPCURL              string
+	CSVFilePath         string           // 来源钱包 CSV 文件路径，"-" 表示标准输入
+	Wallets             []WalletInfo     // 来源钱包，设置后替代 CSVFilePath
+	ReportSource        string           // 报告文件名依据的来源路径，为空时使用 CSVFilePath
+	Targets             []common.Address // 目标地址，按轮询方式分配给每个来源钱包
+	PairedTargets       []common.Address // 与来源钱包按位置一一配对的目标地址，设置后替代 Targets
+	Amount              *big.Int         // 每个钱包转账金额（以 Wei 为单位）
+	MaxWallets          int              // 最大处理钱包数量，0 表示不限制
+	SkipFunded          bool             // 跳过目标地址余额已达到转账金额的钱包，要求每个钱包转入不同的目标地址
+	MaxTotalValue       *big.Int         // 总转账金额上限，nil 表示不限制
+	GasBudget           *gasBudget       // 整个运行的手续费总额预算，nil 表示不限制
+	GasMultiplier       float64          // Gas 价格倍率（相对于网络建议价格）
+	GasPrice            *big.Int         // 固定 gas 价格，设置后替代网络建议价格和倍率
+	GasOracleURL        string           // 外部 gas 价格接口，为空时使用节点建议价格
+	GasOraclePath       string           // gas 价格在接口 JSON 中的字段路径
+	MaxFeeGwei          float64          // EIP-1559 最高费用 (Gwei)
+	MaxPriorityGwei     float64          // EIP-1559 优先费 (Gwei)
+	GasLimit            uint64           // 如果大于 0，则使用固定值
+	GasBuffer           int              // 估算 gas 后增加的缓冲百分比
+	GasBumpPercent      int              // 交易替换失败时重试的 gas 价格提高百分比
+	EstimateEach        bool             // 每个钱包单独估算 gas
+	AllowContract       bool             // 允许目标地址为合约
+	Data                []byte           // 交易 data 字段
+	NoEIP155            bool             // 使用不带重放保护的 Homestead 签名
+	Delay               time.Duration    // 每次转账之间的延迟
+	DelayJitter         time.Duration    // 延迟随机抖动
+	Seed                int64            // 延迟抖动随机数种子，0 表示随机
+	WaitTimeout         time.Duration    // 等待交易确认的超时时间，0 表示一直等待
+	PerWalletTimeout    time.Duration    // 每个钱包的总时间上限，0 表示不限制
+	Retries             int              // 每个钱包遇到临时 RPC 错误时的最大重试次数
+	MaxTotalRetries     int              // 整个运行共享的重试次数上限，0 表示不限制
+	AbortOnInsufficient bool             // 遇到余额不足的钱包时中止全部转账
+	ConfirmEach         bool             // 每笔转账发送前从标准输入逐一确认
+	EstimateOnly        bool             // 只估算并输出计划，不广播交易
+	Prefetch            bool             // 发送前并发预取所有钱包的余额和 nonce
+	PrefetchConcurrency int              // 预取时的最大并发请求数
+	OnlyFailures        bool             // 只输出失败的钱包和最终汇总
+	VerifyAfter         bool             // 全部转账完成后核对目标地址余额增加量
+	DumpRawPath         string           // 已签名交易十六进制的输出文件，为空时不写入
+	ReportFormat        string           // 转账报告格式 (csv, json, jsonl)
+	Display             DisplayUnit      // 日志中转账金额的展示单位
+}
 
-	// 检查文件是否存在
-	fileExists := false
-	if _, err := os.Stat(outputFileName); err == nil {
-		fileExists = true
+// SingleTransferSummary 逐个钱包转账的执行结果汇总
+type SingleTransferSummary struct {
+	TotalWallets      int
+	SuccessCount      int
+	FailCount         int
+	SkipCount         int                         // 用户逐笔确认时跳过的钱包数量
+	InsufficientCount int                         // 余额不足跳过的钱包数量
+	FundedCount       int                         // 目标地址余额已达到转账金额而跳过的钱包数量
+	TotalFee          *big.Int                    // 仅估算模式下预计的总手续费
+	TargetTotals      map[common.Address]*big.Int // 每个目标地址成功转入的总金额
+	Results           []TransferResult            // 写入报告的每个钱包的结果
+	ReportPath        string
+}
+
+// 执行逐个钱包转账
+func ExecuteSingleTransfer(cfg *SingleTransferConfig) (*SingleTransferSummary, error) {
+	summary := &SingleTransferSummary{TotalFee: new(big.Int), TargetTotals: make(map[common.Address]*big.Int)}
+	amountWei := cfg.Amount
+	unit := cfg.Display
+	txData := cfg.Data
+
+	// 读取钱包信息
+	wallets := cfg.Wallets
+	if wallets == nil {
+		var err error
+		wallets, err = readWalletsFromCSV(cfg.CSVFilePath)
+		if err != nil {
+			return summary, fmt.Errorf("读取钱包 CSV 文件失败: %v", err)
+		}
 	}
 
-	// 打开文件（如果不存在则创建）
-	file, err := os.OpenFile(outputFileName, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
-	if err != nil {
-		return fmt.Errorf("打开结果文件失败: %v", err)
+	// 一对一模式：第 i 个来源钱包转入第 i 个目标地址
+	if cfg.PairedTargets != nil && len(cfg.PairedTargets) != len(wallets) {
+		return summary, fmt.Errorf("目标 CSV 包含 %d 个地址，与来源 CSV 的 %d 个钱包数量不一致", len(cfg.PairedTargets), len(wallets))
+	}
+	if cfg.PairedTargets == nil && len(cfg.Targets) == 0 {
+		return summary, fmt.Errorf("没有目标地址")
 	}
-	defer file.Close()
 
-	// 创建 CSV writer
-	writer := csv.NewWriter(file)
-	defer writer.Flush()
+	totalWallets := len(wallets)
+	if cfg.MaxWallets > 0 && totalWallets > cfg.MaxWallets {
+		log.Printf("CSV 文件中包含 %d 个钱包，将只处理前 %d 个钱包", totalWallets, cfg.MaxWallets)
+		wallets = wallets[:cfg.MaxWallets]
+		totalWallets = cfg.MaxWallets
+	}
+	summary.TotalWallets = totalWallets
 
-	// 如果文件是新创建的，写入表头
-	if !fileExists {
-		if err := writer.Write([]string{"address", "txhash", "转账是否成功"}); err != nil {
-			return fmt.Errorf("写入表头失败: %v", err)
-		}
+	// 连接节点前拦截明显错误的金额和钱包数量
+	totalValue := new(big.Int).Mul(amountWei, big.NewInt(int64(totalWallets)))
+	if err := checkRecipientSanity(totalWallets, totalValue, cfg.MaxTotalValue); err != nil {
+		return summary, err
 	}
 
-	// 写入单条数据
-	success := "是"
-	if !result.IsSuccess {
-		success = "否"
+	// 连接以太坊网络
+	client, err := dialClient(context.Background(), cfg.RPCURL)
+	if err != nil {
+		return summary, fmt.Errorf("连接以太坊网络失败: %v", err)
 	}
-	if err := writer.Write([]string{result.Address, result.TxHash, success}); err != nil {
-		return fmt.Errorf("写入数据失败: %v", err)
+
+	// 获取当前网络的平均 gas 价格
+	suggestedGasPrice, err := baseGasPrice(client, cfg.GasOracleURL, cfg.GasOraclePath)
+	if err != nil {
+		return summary, fmt.Errorf("获取网络 gas 价格失败: %v", err)
 	}
 
-	return nil
-}
+	// 应用倍率
+	gasPriceWei := new(big.Int).Mul(
+		suggestedGasPrice,
+		big.NewInt(int64(cfg.GasMultiplier*10000)),
+	)
+	gasPriceWei = gasPriceWei.Div(gasPriceWei, big.NewInt(10000))
+	// 指定固定 gas 价格时直接使用，不再参考网络建议价格
+	if cfg.GasPrice != nil {
+		gasPriceWei = cfg.GasPrice
+	}
+	// EIP-1559 模式：直接使用指定的费用上限，余额和手续费按最高费用计算
+	caps, err := resolveFeeCaps(client, cfg.MaxFeeGwei, cfg.MaxPriorityGwei)
+	if err != nil {
+		return summary, err
+	}
+	if caps != nil {
+		gasPriceWei = caps.FeeCap
+	}
 
-// SingleTransferCmd 是单地址转账命令
-var SingleTransferCmd = &cobra.Command{
-	Use:   "single-transfer",
-	Short: "从 CSV 文件中读取钱包，逐个向指定地址转入固定数量的 BNB",
-	Long:  `从 CSV 文件中读取钱包信息，逐个向指定地址转入固定数量的 BNB。支持设置 gas 价格倍率和转账延迟。`,
-	Run: func(cmd *cobra.Command, args []string) {
-		// 验证参数
-		if singleTransferCSVPath == "" {
-			log.Fatal("请提供钱包 CSV 文件路径 (--csv)")
+	targetAddresses := cfg.Targets
+	if cfg.PairedTargets != nil {
+		targetAddresses = cfg.PairedTargets[:totalWallets]
+	}
+
+	if cfg.SkipFunded {
+		// 多个钱包转入同一目标时目标余额是累计值，无法判断某个钱包是否已转过
+		if len(targetAddresses) < totalWallets {
+			return summary, fmt.Errorf("跳过已到账的目标要求每个钱包转入不同的目标地址，目标地址数量 (%d) 少于钱包数量 (%d)", len(targetAddresses), totalWallets)
 		}
-		if singleTransferTargetAddr == "" {
-			log.Fatal("请提供目标地址 (--target)")
+		wallets, targetAddresses, err = filterFundedTargets(client, wallets, targetAddresses[:totalWallets], amountWei)
+		if err != nil {
+			return summary, err
 		}
-		if singleTransferAmount <= 0 {
-			log.Fatal("转账金额必须大于 0 (--amount)")
+		summary.FundedCount = totalWallets - len(wallets)
+		log.Printf("跳过 %d 个目标地址余额已达到转账金额的钱包", summary.FundedCount)
+		totalWallets = len(wallets)
+		summary.TotalWallets = totalWallets
+		if totalWallets == 0 {
+			log.Printf("所有目标地址余额均已达到转账金额，无需转账")
+			return summary, nil
 		}
-		if singleTransferMaxWallets < 0 {
-			log.Fatal("最大钱包数量不能为负数 (--max-wallets)")
+	}
+
+	// 检测目标是否为合约：合约可能消耗超过 21000 gas，或没有 payable 回退函数而回滚
+	contractTargets := make(map[common.Address]bool)
+	for _, target := range targetAddresses {
+		code, err := client.CodeAt(context.Background(), target, nil)
+		if err != nil {
+			return summary, fmt.Errorf("查询目标地址 %s 的代码失败: %v", target.Hex(), err)
 		}
-		if singleTransferDelay < 0 {
-			log.Fatal("转账延迟不能为负数 (--delay)")
+		if len(code) == 0 {
+			continue
 		}
+		if !cfg.AllowContract {
+			return summary, fmt.Errorf("目标地址 %s 是合约地址，如确认要向合约转账请使用 --allow-contract-target", target.Hex())
+		}
+		log.Printf("警告: 目标地址 %s 是合约地址，转账可能消耗更多 gas 或回滚，将为每个钱包单独估算 gas", target.Hex())
+		contractTargets[target] = true
+	}
 
-		// 连接以太坊网络
-		client, err := ethclient.Dial(singleTransferRPCURL)
-		if err != nil {
-			log.Fatalf("连接以太坊网络失败: %v", err)
+	log.Printf("配置信息:")
+	log.Printf("- RPC URL: %s", cfg.RPCURL)
+	if cfg.PairedTargets != nil {
+		log.Printf("- 目标地址: 与来源钱包一对一配对，共 %d 对", len(targetAddresses))
+	} else if len(targetAddresses) == 1 {
+		log.Printf("- 目标地址: %s", targetAddresses[0].Hex())
+	} else {
+		log.Printf("- 目标地址: %d 个，按轮询方式分配", len(targetAddresses))
+		for i, target := range targetAddresses {
+			log.Printf("  %d. %s", i+1, target.Hex())
 		}
+	}
+	log.Printf("- 每个钱包转账金额: %s", unit.Format(amountWei))
+	log.Printf("- 网络建议 Gas 价格: %.1f Gwei", float64(suggestedGasPrice.Int64())/1e9)
+	if caps != nil {
+		log.Printf("- EIP-1559 费用上限: %s", caps)
+	} else if cfg.GasPrice != nil {
+		log.Printf("- 实际使用 Gas 价格: %.4f Gwei (固定价格 --gas-price)", float64(gasPriceWei.Int64())/1e9)
+	} else {
+		log.Printf("- 实际使用 Gas 价格: %.1f Gwei (%.4f 倍)", float64(gasPriceWei.Int64())/1e9, cfg.GasMultiplier)
+	}
+	if cfg.GasLimit > 0 {
+		log.Printf("- 使用固定 Gas 限制: %d", cfg.GasLimit)
+	} else if cfg.EstimateEach {
+		log.Printf("- Gas 限制: 每个钱包单独估算")
+	} else {
+		log.Printf("- Gas 限制: 估算一次后复用")
+	}
+	if cfg.DelayJitter > 0 {
+		log.Printf("- 转账延迟: %.0f 秒 ± %.1f 秒", cfg.Delay.Seconds(), cfg.DelayJitter.Seconds())
+	} else {
+		log.Printf("- 转账延迟: %.0f 秒", cfg.Delay.Seconds())
+	}
+	if len(txData) > 0 {
+		log.Printf("- 交易数据: %s (%d 字节)", hexutil.Encode(txData), len(txData))
+	}
+	log.Printf("- 总钱包数量: %d", totalWallets)
+	if cfg.EstimateOnly {
+		log.Printf("- 仅估算模式: 不会广播任何交易")
+	}
 
-		// 获取当前网络的平均 gas 价格
-		suggestedGasPrice, err := client.SuggestGasPrice(context.Background())
-		if err != nil {
-			log.Fatalf("获取网络 gas 价格失败: %v", err)
+	// 普通转账到同一目标的 gas 消耗是固定的，每个目标只估算一次并复用
+	cachedGasLimits := make(map[common.Address]uint64)
+	// 每个目标地址成功转入的总金额
+	targetTotals := summary.TargetTotals
+	for _, target := range targetAddresses {
+		targetTotals[target] = new(big.Int)
+	}
+
+	// 每条结果实时追加到报告文件
+	reportSource := cfg.ReportSource
+	if reportSource == "" {
+		reportSource = cfg.CSVFilePath
+	}
+	reportFormat := cfg.ReportFormat
+	if reportFormat == "" {
+		reportFormat = "csv"
+	}
+	reportPath := resultFilePath(reportSource, "_res", reportFormat)
+	summary.ReportPath = reportPath
+	// 当前钱包的上下文，设置 PerWalletTimeout 时带有超时
+	walletCtx, cancelWallet := context.Background(), context.CancelFunc(func() {})
+	defer func() { cancelWallet() }()
+	recordResult := func(result TransferResult) {
+		if !result.IsSuccess && errors.Is(walletCtx.Err(), context.DeadlineExceeded) {
+			result.Error = fmt.Sprintf("超过单个钱包超时时间 %v: %s", cfg.PerWalletTimeout, result.Error)
 		}
+		summary.Results = append(summary.Results, result)
+		if err := appendResult(result, reportPath, reportFormat); err != nil {
+			log.Printf("写入结果文件失败: %v", err)
+		}
+		if cfg.OnlyFailures && !result.IsSuccess {
+			failure := fmt.Sprintf("失败: %s -> %s，原因: %s", result.Address, result.Target, result.Error)
+			if result.TxHash != "" {
+				failure += "，交易哈希: " + result.TxHash
+			}
+			log.Print(failure)
+		}
+	}
 
-		// 应用倍率
-		gasPriceWei := new(big.Int).Mul(
-			suggestedGasPrice,
-			big.NewInt(int64(singleTransferGasMultiplier*10000)),
-		)
-		gasPriceWei = gasPriceWei.Div(gasPriceWei, big.NewInt(10000))
+	// 只输出失败模式下不输出每个钱包的处理进度和成功日志
+	progressf := log.Printf
+	if cfg.OnlyFailures {
+		progressf = func(string, ...interface{}) {}
+	}
 
-		// 转换金额为 Wei
-		amountWei := new(big.Int).Mul(
-			big.NewInt(int64(singleTransferAmount*1e18)),
-			big.NewInt(1),
-		)
+	// 预先并发查询所有钱包的余额和 nonce
+	var prefetchedStates map[common.Address]walletState
+	if cfg.Prefetch {
+		addresses := make([]common.Address, 0, len(wallets))
+		for _, wallet := range wallets {
+			if common.IsHexAddress(wallet.Address) {
+				addresses = append(addresses, common.HexToAddress(wallet.Address))
+			}
+		}
+		log.Printf("正在预取 %d 个钱包的余额和 nonce（并发数 %d）...", len(addresses), cfg.PrefetchConcurrency)
+		start := time.Now()
+		prefetchedStates = prefetchWalletStates(client, addresses, cfg.PrefetchConcurrency)
+		log.Printf("预取完成，成功 %d/%d 个，耗时 %v，其余钱包将在处理时实时查询",
+			len(prefetchedStates), len(addresses), time.Since(start).Round(time.Millisecond))
+	}
 
-		// 读取钱包信息
-		wallets, err := readWalletsFromCSV(singleTransferCSVPath)
+	// 发送前记录目标地址余额，全部转账完成后核对余额增加量
+	var balances *balanceCheck
+	if cfg.VerifyAfter && !cfg.EstimateOnly {
+		balances, err = newBalanceCheck(client, targetAddresses)
 		if err != nil {
-			log.Fatalf("读取钱包 CSV 文件失败: %v", err)
+			return summary, err
 		}
+	}
 
-		totalWallets := len(wallets)
-		if singleTransferMaxWallets > 0 && totalWallets > singleTransferMaxWallets {
-			log.Printf("CSV 文件中包含 %d 个钱包，将只处理前 %d 个钱包", totalWallets, singleTransferMaxWallets)
-			wallets = wallets[:singleTransferMaxWallets]
-			totalWallets = singleTransferMaxWallets
-		}
+	var stdinReader *bufio.Reader
+	if cfg.ConfirmEach {
+		stdinReader = bufio.NewReader(os.Stdin)
+	}
+
+	// 每个来源地址的 nonce 管理器，交易发送成功后才递增
+	nonceManagers := make(map[common.Address]*lib.NonceManager)
 
-		// 验证目标地址
-		targetAddress := common.HexToAddress(singleTransferTargetAddr)
-		if !common.IsHexAddress(singleTransferTargetAddr) {
-			log.Fatalf("无效的目标地址: %s", singleTransferTargetAddr)
+	// 逐个处理钱包
+	jitterRand := newJitterRand(cfg.Seed)
+	budget := newRetryBudget(cfg.MaxTotalRetries)
+	for i, wallet := range wallets {
+		if budget.exhausted {
+			return summary, fmt.Errorf("%w，已处理 %d/%d 个钱包（成功 %d，失败 %d）", errRetryBudgetExhausted, i, totalWallets, summary.SuccessCount, summary.FailCount)
+		}
+		cancelWallet()
+		walletCtx, cancelWallet = walletContext(cfg.PerWalletTimeout)
+		targetAddress := targetAddresses[i%len(targetAddresses)]
+		progressf("\n处理第 %d/%d 个钱包: %s -> %s", i+1, totalWallets, wallet.Address, targetAddress.Hex())
+
+		result := TransferResult{
+			Address: wallet.Address,
+			Target:  targetAddress.Hex(),
+			Amount:  amountWei.String(),
 		}
 
-		log.Printf("配置信息:")
-		log.Printf("- RPC URL: %s", singleTransferRPCURL)
-		log.Printf("- 目标地址: %s", targetAddress.Hex())
-		log.Printf("- 每个钱包转账金额: %.4f BNB", singleTransferAmount)
-		log.Printf("- 网络建议 Gas 价格: %.1f Gwei", float64(suggestedGasPrice.Int64())/1e9)
-		log.Printf("- 实际使用 Gas 价格: %.1f Gwei (%.4f 倍)", float64(gasPriceWei.Int64())/1e9, singleTransferGasMultiplier)
-		if singleTransferGasLimit > 0 {
-			log.Printf("- 使用固定 Gas 限制: %d", singleTransferGasLimit)
-		} else {
-			log.Printf("- Gas 限制: 动态估算")
+		// 解析私钥
+		privateKey, err := crypto.HexToECDSA(strings.TrimPrefix(wallet.PrivateKey, "0x"))
+		if err != nil {
+			log.Printf("解析私钥失败: %v", err)
+			result.Error = "解析私钥失败"
+			recordResult(result)
+			summary.FailCount++
+			continue
 		}
-		log.Printf("- 转账延迟: %d 秒", singleTransferDelay)
-		log.Printf("- 总钱包数量: %d", totalWallets)
 
-		// 逐个处理钱包
-		successCount := 0
-		failCount := 0
-		for i, wallet := range wallets {
-			log.Printf("\n处理第 %d/%d 个钱包: %s", i+1, totalWallets, wallet.Address)
+		// 获取发送者地址
+		fromAddress := crypto.PubkeyToAddress(privateKey.PublicKey)
 
-			result := TransferResult{
-				Address: wallet.Address,
+		// 获取 nonce（优先使用预取结果），同一地址在 CSV 中重复出现时沿用本地递增的 nonce
+		state, prefetched := prefetchedStates[fromAddress]
+		nonces, ok := nonceManagers[fromAddress]
+		if !ok {
+			nonces = lib.NewNonceManager(client, fromAddress)
+			if prefetched {
+				nonces.Set(state.Nonce)
 			}
+			nonceManagers[fromAddress] = nonces
+		}
+		var nonce uint64
+		err = withRetries(walletCtx, "获取 nonce ", cfg.Retries, budget, func() error {
+			var err error
+			nonce, err = nonces.Peek(walletCtx)
+			return err
+		})
+		if err != nil {
+			log.Printf("获取 nonce 失败: %v", err)
+			result.Error = "获取nonce失败"
+			recordResult(result)
+			summary.FailCount++
+			continue
+		}
 
-			// 解析私钥
-			privateKey, err := crypto.HexToECDSA(strings.TrimPrefix(wallet.PrivateKey, "0x"))
+		// 估算 gas
+		gasLimit := cfg.GasLimit
+		if gasLimit == 0 && !cfg.EstimateEach && !contractTargets[targetAddress] {
+			gasLimit = cachedGasLimits[targetAddress]
+		}
+		if gasLimit == 0 {
+			msg := ethereum.CallMsg{
+				From:  fromAddress,
+				To:    &targetAddress,
+				Value: amountWei,
+				Data:  txData,
+			}
+			var estimatedGas uint64
+			err := withRetries(walletCtx, "估算 gas ", cfg.Retries, budget, func() error {
+				var err error
+				estimatedGas, err = client.EstimateGas(walletCtx, msg)
+				return err
+			})
 			if err != nil {
-				log.Printf("解析私钥失败: %v", err)
-				result.TxHash = "解析私钥失败"
-				result.IsSuccess = false
-				if err := appendResultToCSV(result, singleTransferCSVPath); err != nil {
-					log.Printf("写入结果文件失败: %v", err)
-				}
-				failCount++
+				log.Printf("估算 gas 失败: %v", err)
+				result.Error = "估算gas失败"
+				recordResult(result)
+				summary.FailCount++
 				continue
 			}
+			gasLimit = applyGasBuffer(estimatedGas, cfg.GasBuffer)
+			if !cfg.EstimateEach && !contractTargets[targetAddress] {
+				cachedGasLimits[targetAddress] = gasLimit
+				progressf("估算 gas 限制: %d (包含 %d%% 缓冲)，后续转入该目标的钱包将复用该值", gasLimit, cfg.GasBuffer)
+			}
+		}
+
+		// 检查余额是否足够支付转账金额和 gas
+		balance := state.Balance
+		if !prefetched {
+			err = withRetries(walletCtx, "查询余额", cfg.Retries, budget, func() error {
+				var err error
+				balance, err = client.BalanceAt(walletCtx, fromAddress, nil)
+				return err
+			})
+		}
+		if err != nil {
+			log.Printf("查询余额失败: %v", err)
+			result.Error = "查询余额失败"
+			recordResult(result)
+			summary.FailCount++
+			continue
+		}
+		required := new(big.Int).Mul(new(big.Int).SetUint64(gasLimit), gasPriceWei)
+		required.Add(required, amountWei)
+		if balance.Cmp(required) < 0 {
+			log.Printf("余额不足以支付金额和 gas，跳过该钱包: 余额 %.8f BNB，需要 %.8f BNB", weiToEther(balance), weiToEther(required))
+			result.Error = "余额不足以支付金额和gas"
+			recordResult(result)
+			if cfg.AbortOnInsufficient {
+				return summary, fmt.Errorf("钱包 %s 余额不足，已中止全部转账 (--abort-on-insufficient)，已处理 %d/%d 个钱包（成功 %d，失败 %d）",
+					fromAddress.Hex(), i+1, totalWallets, summary.SuccessCount, summary.FailCount)
+			}
+			summary.InsufficientCount++
+			continue
+		}
 
-			// 获取发送者地址
-			fromAddress := crypto.PubkeyToAddress(privateKey.PublicKey)
+		// 手续费预算：按本笔最高手续费判断是否还能发送
+		if cfg.GasBudget != nil && !cfg.EstimateOnly {
+			progressf("剩余手续费预算: %s", formatEther(cfg.GasBudget.remaining()))
+			if err := cfg.GasBudget.check(gasLimit, gasPriceWei); err != nil {
+				return summary, fmt.Errorf("钱包 %s 未发送: %v，已处理 %d/%d 个钱包（成功 %d，失败 %d）",
+					fromAddress.Hex(), err, i, totalWallets, summary.SuccessCount, summary.FailCount)
+			}
+		}
 
-			// 获取 nonce
-			nonce, err := client.PendingNonceAt(context.Background(), fromAddress)
-			if err != nil {
-				log.Printf("获取 nonce 失败: %v", err)
-				result.TxHash = "获取nonce失败"
-				result.IsSuccess = false
-				if err := appendResultToCSV(result, singleTransferCSVPath); err != nil {
-					log.Printf("写入结果文件失败: %v", err)
-				}
-				failCount++
+		// 仅估算模式：输出计划后跳过发送
+		if cfg.EstimateOnly {
+			fee := new(big.Int).Mul(new(big.Int).SetUint64(gasLimit), gasPriceWei)
+			remaining := new(big.Int).Sub(balance, required)
+			progressf("[仅估算] nonce: %d，转账金额: %s，Gas 限制: %d，手续费: %.8f BNB，转账后余额: %.8f BNB",
+				nonce, unit.Format(amountWei), gasLimit, weiToEther(fee), weiToEther(remaining))
+			summary.TotalFee.Add(summary.TotalFee, fee)
+			nonces.Set(nonce + 1)
+			summary.SuccessCount++
+			targetTotals[targetAddress].Add(targetTotals[targetAddress], amountWei)
+			continue
+		}
+
+		// 逐笔确认
+		if cfg.ConfirmEach {
+			action := confirmTransfer(stdinReader, fromAddress, targetAddress, amountWei, unit, gasLimit, gasPriceWei)
+			if action == "abort" {
+				log.Printf("用户中止了剩余的全部转账")
+				break
+			}
+			if action == "skip" {
+				log.Printf("用户跳过了该钱包")
+				summary.SkipCount++
 				continue
 			}
+		}
 
-			// 估算 gas
-			gasLimit := singleTransferGasLimit
-			if gasLimit == 0 {
-				msg := ethereum.CallMsg{
-					From:  fromAddress,
-					To:    &targetAddress,
-					Value: amountWei,
-				}
-				estimatedGas, err := client.EstimateGas(context.Background(), msg)
-				if err != nil {
-					log.Printf("估算 gas 失败: %v", err)
-					result.TxHash = "估算gas失败"
-					result.IsSuccess = false
-					if err := appendResultToCSV(result, singleTransferCSVPath); err != nil {
-						log.Printf("写入结果文件失败: %v", err)
-					}
-					failCount++
-					continue
-				}
-				gasLimit = estimatedGas * 12 / 10 // 增加 20% 的缓冲
+		chainID, err := client.ChainID(walletCtx)
+		if err != nil {
+			log.Printf("获取链 ID 失败: %v", err)
+			result.Error = "获取链ID失败"
+			recordResult(result)
+			summary.FailCount++
+			continue
+		}
+
+		// 创建交易
+		tx := types.NewTransaction(
+			nonce,
+			targetAddress,
+			amountWei,
+			gasLimit,
+			gasPriceWei,
+			txData,
+		)
+		var signer types.Signer = types.NewEIP155Signer(chainID)
+		if cfg.NoEIP155 {
+			signer = types.HomesteadSigner{}
+		}
+		if caps != nil {
+			tx = caps.newTx(chainID, nonce, targetAddress, amountWei, gasLimit, txData)
+			signer = types.NewLondonSigner(chainID)
+		}
+
+		// 签名交易
+		signedTx, err := types.SignTx(tx, signer, privateKey)
+		if err != nil {
+			log.Printf("签名交易失败: %v", err)
+			result.Error = "签名交易失败"
+			recordResult(result)
+			summary.FailCount++
+			continue
+		}
+		// 签名后立即写入原始交易，发送失败的交易也能用 broadcast --raw-file 重新广播
+		dumpRaw := func(tx *types.Transaction) {
+			if cfg.DumpRawPath == "" {
+				return
+			}
+			if err := appendRawTransaction(cfg.DumpRawPath, tx); err != nil {
+				log.Printf("写入原始交易失败: %v", err)
+			}
+		}
+		dumpRaw(signedTx)
+
+		// 发送交易，重试时发送同一笔已签名交易，不会重复转账
+		err = withRetries(walletCtx, "发送交易", cfg.Retries, budget, func() error {
+			return client.SendTransaction(walletCtx, signedTx)
+		})
+		if err != nil && isReplacementError(err) {
+			// 节点中已有相同 nonce 的交易，提高 gas 价格重试一次
+			bumpedGasPrice := bumpGasPrice(gasPriceWei, cfg.GasBumpPercent)
+			log.Printf("发送交易失败: %v，将 gas 价格提高 %d%% 至 %.4f Gwei 后使用 nonce %d 重试一次",
+				err, cfg.GasBumpPercent, float64(bumpedGasPrice.Int64())/1e9, nonce)
+			bumpedTx := types.NewTransaction(nonce, targetAddress, amountWei, gasLimit, bumpedGasPrice, txData)
+			if caps != nil {
+				bumpedTx = caps.bump(cfg.GasBumpPercent).newTx(chainID, nonce, targetAddress, amountWei, gasLimit, txData)
+			}
+			signedTx, err = types.SignTx(bumpedTx, signer, privateKey)
+			if err == nil {
+				dumpRaw(signedTx)
+				err = client.SendTransaction(walletCtx, signedTx)
 			}
+		}
+		if err != nil {
+			log.Printf("发送交易失败: %v", err)
+			if !cfg.NoEIP155 && isEIP155Error(err) {
+				log.Printf("节点可能不支持 EIP-155 重放保护签名，如确认该网络不支持 EIP-155，请使用 --no-eip155 重试")
+			}
+			result.Error = "发送交易失败"
+			recordResult(result)
+			summary.FailCount++
+			continue
+		}
+
+		nonces.Set(nonce + 1)
+		result.TxHash = signedTx.Hash().Hex()
+		progressf("交易已发送，交易哈希: %s", result.TxHash)
 
-			// 创建交易
-			tx := types.NewTransaction(
-				nonce,
-				targetAddress,
-				amountWei,
-				gasLimit,
-				gasPriceWei,
-				nil,
-			)
-
-			// 签名交易
-			chainID, err := client.ChainID(context.Background())
+		// 等待交易确认
+		receipt, txState, err := waitMinedContext(walletCtx, client, signedTx, cfg.WaitTimeout)
+		result.State = txState
+		if receipt != nil && cfg.GasBudget != nil {
+			cfg.GasBudget.record(receipt, signedTx)
+		}
+		if receipt == nil {
+			log.Printf("等待交易确认失败 (状态: %s): %v", txState, err)
+			result.Error = "等待交易确认失败"
+			recordResult(result)
+			summary.FailCount++
+			continue
+		}
+
+		if receipt.Status == 0 {
+			log.Printf("交易执行失败，交易哈希: %s", receipt.TxHash.Hex())
+			result.GasUsed = receipt.GasUsed
+			result.Error = "交易执行失败"
+			recordResult(result)
+			summary.FailCount++
+			continue
+		}
+
+		result.IsSuccess = true
+		result.GasUsed = receipt.GasUsed
+		recordResult(result)
+		progressf("转账成功！交易哈希: %s，实际使用 gas: %d",
+			receipt.TxHash.Hex(),
+			receipt.GasUsed,
+		)
+		summary.SuccessCount++
+		targetTotals[targetAddress].Add(targetTotals[targetAddress], amountWei)
+		if balances != nil {
+			balances.add(targetAddress, amountWei)
+		}
+
+		// 如果不是最后一个钱包，等待指定的延迟时间
+		if i < totalWallets-1 && (cfg.Delay > 0 || cfg.DelayJitter > 0) {
+			wait := jitteredDelay(jitterRand, cfg.Delay, cfg.DelayJitter)
+			progressf("等待 %.1f 秒后处理下一个钱包...", wait.Seconds())
+			time.Sleep(wait)
+		}
+	}
+
+	result := fmt.Sprintf("\n转账完成！成功: %d，失败: %d", summary.SuccessCount, summary.FailCount)
+	if cfg.EstimateOnly {
+		result = fmt.Sprintf("\n估算完成（未广播任何交易）！可发送: %d，失败: %d，预计总手续费: %.8f BNB",
+			summary.SuccessCount, summary.FailCount, weiToEther(summary.TotalFee))
+	}
+	if summary.InsufficientCount > 0 {
+		result += fmt.Sprintf("，余额不足跳过: %d", summary.InsufficientCount)
+	}
+	if summary.SkipCount > 0 {
+		result += fmt.Sprintf("，用户跳过: %d", summary.SkipCount)
+	}
+	if summary.FundedCount > 0 {
+		result += fmt.Sprintf("，已到账跳过: %d", summary.FundedCount)
+	}
+	log.Print(result)
+	if cfg.PairedTargets != nil {
+		log.Printf("每对钱包的转账结果已写入 %s", reportPath)
+	} else if len(targetAddresses) > 1 {
+		log.Printf("各目标地址转入总额:")
+		for _, target := range targetAddresses {
+			log.Printf("- %s: %s", target.Hex(), unit.Format(targetTotals[target]))
+		}
+	}
+	if balances != nil {
+		logBalanceVerification(balances, unit)
+	}
+	return summary, nil
+}
+
+// SingleTransferCmd 是单地址转账命令
+var SingleTransferCmd = &cobra.Command{
+	Use:     "single-transfer",
+	Aliases: []string{"st"},
+	Short:   "从 CSV 文件中读取钱包，逐个向指定地址转入固定数量的 BNB",
+	Long:    `从 CSV 文件中读取钱包信息，逐个向指定地址转入固定数量的 BNB。支持设置 gas 价格倍率和转账延迟。`,
+	Run: func(cmd *cobra.Command, args []string) {
+		// 验证参数
+		if singleTransferCSVPath == "" && singleTransferMnemonic == "" {
+			log.Fatal("请提供钱包 CSV 文件路径 (--csv) 或助记词 (--mnemonic)")
+		}
+		if singleTransferCSVPath != "" && singleTransferMnemonic != "" {
+			log.Fatal("--csv 和 --mnemonic 不能同时使用")
+		}
+		if singleTransferIndex < 0 {
+			log.Fatal("地址索引不能为负数 (--index)")
+		}
+		if singleTransferTargetAddr == "" && singleTransferTargets == "" && singleTransferTargetCSV == "" {
+			log.Fatal("请提供目标地址 (--target、--targets 或 --target-csv)")
+		}
+		if singleTransferCSVPath == stdinPath && singleTransferConfirmEach {
+			log.Fatal("从标准输入读取钱包时不能使用 --confirm-each（确认需要读取标准输入）")
+		}
+		if singleTransferCSVPath == stdinPath && singleTransferTargetCSV == stdinPath {
+			log.Fatal("钱包和目标地址 CSV 不能同时从标准输入读取")
+		}
+		if singleTransferTargetCSV != "" && singleTransferTargets != "" {
+			log.Fatal("--target-csv 和 --targets 不能同时使用")
+		}
+		amountWei, err := parseAmount(singleTransferAmount)
+		if err != nil {
+			log.Fatalf("转账金额无效 (--amount): %v", err)
+		}
+		if amountWei.Sign() <= 0 {
+			log.Fatal("转账金额必须大于 0 (--amount)")
+		}
+		// 按美元金额和实时价格换算每个钱包的转账金额
+		if singleTransferAmountUSD != "" {
+			if cmd.Flags().Changed("amount") {
+				log.Fatal("--amount 和 --amount-usd 不能同时使用")
+			}
+			amountWei, err = resolveUSDAmount(singleTransferAmountUSD, singleTransferPriceURL, singleTransferPricePath, singleTransferPriceFallback)
 			if err != nil {
-				log.Printf("获取链 ID 失败: %v", err)
-				result.TxHash = "获取链ID失败"
-				result.IsSuccess = false
-				if err := appendResultToCSV(result, singleTransferCSVPath); err != nil {
-					log.Printf("写入结果文件失败: %v", err)
-				}
-				failCount++
-				continue
+				log.Fatal(err)
 			}
-
-			signedTx, err := types.SignTx(tx, types.NewEIP155Signer(chainID), privateKey)
+		}
+		if singleTransferGasPrice < 0 {
+			log.Fatal("gas 价格不能为负数 (--gas-price)")
+		}
+		if singleTransferGasPrice > 0 && cmd.Flags().Changed("gas-multiplier") {
+			log.Fatal("--gas-price 和 --gas-multiplier 不能同时使用")
+		}
+		if singleTransferGasPrice > 0 && (singleTransferMaxFeeGwei > 0 || singleTransferMaxPriorityGwei > 0) {
+			log.Fatal("--gas-price 不能与 --max-fee-gwei / --max-priority-gwei 同时使用")
+		}
+		if singleTransferNoEIP155 && (singleTransferMaxFeeGwei > 0 || singleTransferMaxPriorityGwei > 0) {
+			log.Fatal("--no-eip155 不能与 --max-fee-gwei / --max-priority-gwei 同时使用（EIP-1559 交易必须带链 ID）")
+		}
+		if singleTransferDecimals < 0 {
+			log.Fatal("小数位数不能为负数 (--decimals)")
+		}
+		unit := DisplayUnit{Symbol: singleTransferSymbol, Decimals: singleTransferDecimals}
+		// 交易 data 字段（例如交易所充值要求的备注）
+		var txData []byte
+		if singleTransferData != "" {
+			dataHex := singleTransferData
+			if !strings.HasPrefix(dataHex, "0x") && !strings.HasPrefix(dataHex, "0X") {
+				dataHex = "0x" + dataHex
+			}
+			data, err := hexutil.Decode(dataHex)
 			if err != nil {
-				log.Printf("签名交易失败: %v", err)
-				result.TxHash = "签名交易失败"
-				result.IsSuccess = false
-				if err := appendResultToCSV(result, singleTransferCSVPath); err != nil {
-					log.Printf("写入结果文件失败: %v", err)
-				}
-				failCount++
-				continue
+				log.Fatalf("无效的十六进制数据 (--data): %v", err)
 			}
+			txData = data
+		}
+		if singleTransferGasBuffer < 0 {
+			log.Fatal("gas 缓冲百分比不能为负数 (--gas-buffer)")
+		}
+		if singleTransferPerWalletTimeout < 0 {
+			log.Fatal("单个钱包超时时间不能为负数 (--per-wallet-timeout)")
+		}
+		if singleTransferRetries < 0 || singleTransferMaxTotalRetries < 0 {
+			log.Fatal("重试次数不能为负数 (--retries, --max-total-retries)")
+		}
+		if singleTransferMaxWallets < 0 {
+			log.Fatal("最大钱包数量不能为负数 (--max-wallets)")
+		}
+		if singleTransferDelay < 0 {
+			log.Fatal("转账延迟不能为负数 (--delay)")
+		}
+		delay := time.Duration(singleTransferDelay) * time.Second
+		delayJitter, err := parseDelayJitter(singleTransferDelayJitter, delay)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := validateReportFormat(singleTransferReportFormat); err != nil {
+			log.Fatal(err)
+		}
+		if singleTransferPrefetch && singleTransferPrefetchConcurrency <= 0 {
+			log.Fatal("预取并发数必须大于 0 (--prefetch-concurrency)")
+		}
+		if singleTransferGasBumpPercent < 10 {
+			log.Fatal("gas 价格提高百分比不能小于 10，否则节点不会接受替换交易 (--gas-bump-percent)")
+		}
 
-			// 发送交易
-			err = client.SendTransaction(context.Background(), signedTx)
+		// 指定 --mnemonic 时只使用从助记词派生的一个钱包
+		var wallets []WalletInfo
+		var reportSource string
+		if singleTransferMnemonic != "" {
+			mnemonic := strings.Join(strings.Fields(singleTransferMnemonic), " ")
+			if check := checkMnemonic(mnemonic); !check.Valid {
+				log.Fatalf("助记词无效 (--mnemonic): %s", check.Reason)
+			}
+			address, privateKey, err := lib.DeriveFromMnemonic(mnemonic, uint32(singleTransferIndex))
 			if err != nil {
-				log.Printf("发送交易失败: %v", err)
-				result.TxHash = "发送交易失败"
-				result.IsSuccess = false
-				if err := appendResultToCSV(result, singleTransferCSVPath); err != nil {
-					log.Printf("写入结果文件失败: %v", err)
-				}
-				failCount++
-				continue
+				log.Fatalf("从助记词派生钱包失败: %v", err)
 			}
+			log.Printf("从助记词派生来源钱包: %s (路径 %s)", address.Hex(), lib.DerivationPath(uint32(singleTransferIndex)))
+			wallets = []WalletInfo{{Address: address.Hex(), PrivateKey: privateKey, Mnemonic: mnemonic}}
+			reportSource = "mnemonic_" + address.Hex()
+		}
 
-			result.TxHash = signedTx.Hash().Hex()
-			log.Printf("交易已发送，交易哈希: %s", result.TxHash)
-
-			// 等待交易确认
-			receipt, err := bind.WaitMined(context.Background(), client, signedTx)
+		// 验证目标地址，--target-csv 时与来源钱包一对一配对
+		var targets, pairedTargets []common.Address
+		if singleTransferTargetCSV != "" {
+			pairedTargets, err = readAddressesFromCSV(singleTransferTargetCSV)
 			if err != nil {
-				log.Printf("等待交易确认失败: %v", err)
-				result.IsSuccess = false
-				if err := appendResultToCSV(result, singleTransferCSVPath); err != nil {
-					log.Printf("写入结果文件失败: %v", err)
-				}
-				failCount++
-				continue
+				log.Fatalf("读取目标 CSV 文件失败: %v", err)
 			}
-
-			if receipt.Status == 0 {
-				log.Printf("交易执行失败，交易哈希: %s", receipt.TxHash.Hex())
-				result.IsSuccess = false
-				if err := appendResultToCSV(result, singleTransferCSVPath); err != nil {
-					log.Printf("写入结果文件失败: %v", err)
+		} else {
+			targetAddrs := []string{singleTransferTargetAddr}
+			if singleTransferTargets != "" {
+				targetAddrs = strings.Split(singleTransferTargets, ",")
+			}
+			for _, addr := range targetAddrs {
+				addr = strings.TrimSpace(addr)
+				if !common.IsHexAddress(addr) {
+					log.Fatalf("无效的目标地址: %s", addr)
 				}
-				failCount++
-				continue
+				targets = append(targets, common.HexToAddress(addr))
 			}
+		}
 
-			result.IsSuccess = true
-			if err := appendResultToCSV(result, singleTransferCSVPath); err != nil {
-				log.Printf("写入结果文件失败: %v", err)
-			}
-			log.Printf("转账成功！交易哈希: %s，实际使用 gas: %d",
-				receipt.TxHash.Hex(),
-				receipt.GasUsed,
-			)
-			successCount++
-
-			// 如果不是最后一个钱包，等待指定的延迟时间
-			if i < totalWallets-1 && singleTransferDelay > 0 {
-				log.Printf("等待 %d 秒后处理下一个钱包...", singleTransferDelay)
-				time.Sleep(time.Duration(singleTransferDelay) * time.Second)
+		maxTotalValue, err := parseMaxTotalValue(singleTransferMaxTotalValue)
+		if err != nil {
+			log.Fatal(err)
+		}
+		feeBudget, err := parseMaxGasTotal(singleTransferMaxGasTotal)
+		if err != nil {
+			log.Fatal(err)
+		}
+		var fixedGasPrice *big.Int
+		if singleTransferGasPrice > 0 {
+			fixedGasPrice = gweiToWei(singleTransferGasPrice)
+		}
+
+		// 未指定 --rpc 时自动选择最快的节点
+		if singleTransferRPCNodesFile != "" && !singleTransferAutoRPC {
+			log.Fatal("--rpc-nodes-file 需要与 --auto-rpc 一起使用")
+		}
+		if singleTransferAutoRPC && !cmd.Flags().Changed("rpc") {
+			url, err := autoSelectRPC(singleTransferRPCNodesFile, singleTransferExpectChainID)
+			if err != nil {
+				log.Fatalf("自动选择 RPC 节点失败: %v", err)
 			}
+			singleTransferRPCURL = url
 		}
 
-		log.Printf("\n转账完成！成功: %d，失败: %d", successCount, failCount)
+		cfg := &SingleTransferConfig{
+			RPCURL:              singleTransferRPCURL,
+			CSVFilePath:         singleTransferCSVPath,
+			Wallets:             wallets,
+			ReportSource:        reportSource,
+			Targets:             targets,
+			PairedTargets:       pairedTargets,
+			Amount:              amountWei,
+			MaxWallets:          singleTransferMaxWallets,
+			SkipFunded:          singleTransferSkipFunded,
+			MaxTotalValue:       maxTotalValue,
+			GasBudget:           feeBudget,
+			GasMultiplier:       singleTransferGasMultiplier,
+			GasPrice:            fixedGasPrice,
+			GasOracleURL:        singleTransferGasOracleURL,
+			GasOraclePath:       singleTransferGasOraclePath,
+			MaxFeeGwei:          singleTransferMaxFeeGwei,
+			MaxPriorityGwei:     singleTransferMaxPriorityGwei,
+			GasLimit:            singleTransferGasLimit,
+			GasBuffer:           singleTransferGasBuffer,
+			GasBumpPercent:      singleTransferGasBumpPercent,
+			EstimateEach:        singleTransferEstimateEach,
+			AllowContract:       singleTransferAllowContract,
+			Data:                txData,
+			NoEIP155:            singleTransferNoEIP155,
+			Delay:               delay,
+			DelayJitter:         delayJitter,
+			Seed:                singleTransferSeed,
+			WaitTimeout:         singleTransferWaitTimeout,
+			PerWalletTimeout:    singleTransferPerWalletTimeout,
+			Retries:             singleTransferRetries,
+			MaxTotalRetries:     singleTransferMaxTotalRetries,
+			AbortOnInsufficient: singleTransferAbortOnInsufficient,
+			ConfirmEach:         singleTransferConfirmEach,
+			EstimateOnly:        singleTransferEstimateOnly,
+			Prefetch:            singleTransferPrefetch,
+			PrefetchConcurrency: singleTransferPrefetchConcurrency,
+			OnlyFailures:        singleTransferOnlyFailures,
+			VerifyAfter:         singleTransferVerifyAfter,
+			DumpRawPath:         singleTransferDumpRaw,
+			ReportFormat:        singleTransferReportFormat,
+			Display:             unit,
+		}
+		if _, err := ExecuteSingleTransfer(cfg); err != nil {
+			log.Fatal(err)
+		}
 	},
 }
 
 func init() {
 	SingleTransferCmd.Flags().StringVar(&singleTransferRPCURL, "rpc", "https://bsc-dataseed.binance.org/", "以太坊 RPC URL")
-	SingleTransferCmd.Flags().StringVar(&singleTransferCSVPath, "csv", "", "钱包 CSV 文件路径")
+	SingleTransferCmd.Flags().StringVar(&singleTransferCSVPath, "csv", "", "钱包 CSV 文件路径，- 表示从标准输入读取")
+	SingleTransferCmd.Flags().StringVar(&singleTransferMnemonic, "mnemonic", "", "直接使用助记词（用引号括起来）派生来源钱包，无需 CSV 文件，用于从已知助记词转出资金（注意助记词会留在 shell 历史中）")
+	SingleTransferCmd.Flags().IntVar(&singleTransferIndex, "index", 0, "使用 --mnemonic 时派生的地址索引，路径为 m/44'/60'/0'/0/index")
 	SingleTransferCmd.Flags().StringVar(&singleTransferTargetAddr, "target", "0x774d0d4281217deDB7ae7797D69968D6Ea07c1Ae", "目标地址")
-	SingleTransferCmd.Flags().Float64Var(&singleTransferAmount, "amount", 0.0001, "每个钱包转账金额 (BNB)")
+	SingleTransferCmd.Flags().StringVar(&singleTransferTargetCSV, "target-csv", "", "目标地址 CSV（需要地址列），第 i 个来源钱包转入第 i 个目标地址，两个文件的行数必须一致")
+	SingleTransferCmd.Flags().StringVar(&singleTransferTargets, "targets", "", "多个目标地址（逗号分隔），每个钱包依次轮询转入下一个目标")
+	SingleTransferCmd.Flags().BoolVar(&singleTransferAbortOnInsufficient, "abort-on-insufficient", false, "遇到第一个余额不足的钱包时中止全部转账（默认跳过该钱包继续），用于所有钱包都应有足够余额的场景")
+	SingleTransferCmd.Flags().IntVar(&singleTransferGasBuffer, "gas-buffer", defaultGasBuffer, "估算 gas 后增加的缓冲百分比，0 表示原样使用估算值（目标为 gas 消耗随状态变化的合约时缓冲过小可能 out of gas）")
+	SingleTransferCmd.Flags().StringVar(&singleTransferMaxGasTotal, "max-gas-total", "", "整个运行可花费的手续费总额（按回执 gasUsed × gas 价格累计，支持 wei/gwei/ether 单位后缀），下一笔的最高手续费会超出剩余预算时中止（为空表示不限制）")
+	SingleTransferCmd.Flags().StringVar(&singleTransferMaxTotalValue, "max-total-value", "", "总转账金额（每个钱包金额 × 钱包数量）上限，超过时在连接节点前拒绝运行，用于发现金额多打 0 等错误（为空表示不限制）")
+	SingleTransferCmd.Flags().StringVar(&singleTransferAmountUSD, "amount-usd", "", "以美元计的每个钱包转账金额，运行时按 --price-url 返回的原生币价格换算，不能与 --amount 同时使用")
+	SingleTransferCmd.Flags().StringVar(&singleTransferPriceURL, "price-url", "", "返回原生币美元价格的 HTTP JSON 接口")
+	SingleTransferCmd.Flags().StringVar(&singleTransferPricePath, "price-path", ".price", "美元价格在接口 JSON 中的字段路径，例如 .price 或 .binancecoin.usd")
+	SingleTransferCmd.Flags().Float64Var(&singleTransferPriceFallback, "price-fallback", 0, "价格接口不可用时使用的备用美元价格 (0 表示获取失败时中止)")
+	SingleTransferCmd.Flags().StringVar(&singleTransferAmount, "amount", "0.0001", "每个钱包转账金额 (BNB)，支持 1,000.5、1e-3、0.000_1 及 wei/gwei/ether 单位后缀")
+	SingleTransferCmd.Flags().Float64Var(&singleTransferMaxFeeGwei, "max-fee-gwei", 0, "EIP-1559 最高费用 maxFeePerGas (Gwei)，设置后发送 EIP-1559 交易，未设置时取 2 倍基础费用加优先费")
+	SingleTransferCmd.Flags().Float64Var(&singleTransferMaxPriorityGwei, "max-priority-gwei", 0, "EIP-1559 优先费 maxPriorityFeePerGas (Gwei)，设置后发送 EIP-1559 交易，未设置时取节点建议值，不能高于最高费用")
+	SingleTransferCmd.Flags().StringVar(&singleTransferGasOracleURL, "gas-oracle-url", "", "外部 gas 价格接口 (HTTP JSON)，设置后替代节点建议价格作为倍率的基础，接口不可用时回退到节点")
+	SingleTransferCmd.Flags().StringVar(&singleTransferGasOraclePath, "gas-oracle-path", ".fast", "gas 价格在接口 JSON 中的字段路径（以点分隔，值以 Gwei 为单位），例如 .fast 或 result.FastGasPrice")
 	SingleTransferCmd.Flags().Float64Var(&singleTransferGasMultiplier, "gas-multiplier", 1.0001, "Gas 价格倍率 (相对于网络平均 gas 价格)")
+	SingleTransferCmd.Flags().Float64Var(&singleTransferGasPrice, "gas-price", 0, "固定的 Gas 价格 (Gwei)，大于 0 时替代网络建议价格和倍率，不能与 --gas-multiplier 同时使用")
 	SingleTransferCmd.Flags().Uint64Var(&singleTransferGasLimit, "gas-limit", 0, "固定的 Gas 限制 (如果设置，将跳过估算)")
 	SingleTransferCmd.Flags().IntVar(&singleTransferMaxWallets, "max-wallets", 0, "最大处理钱包数量 (0 表示不限制)")
+	SingleTransferCmd.Flags().BoolVar(&singleTransferSkipFunded, "skip-funded", false, "发送前查询每个目标地址余额，跳过余额已达到转账金额的钱包（要求每个钱包转入不同的目标地址，即 --targets 数量不少于钱包数量）")
 	SingleTransferCmd.Flags().IntVar(&singleTransferDelay, "delay", 30, "每次转账之间的延迟（秒）")
+	SingleTransferCmd.Flags().StringVar(&singleTransferDelayJitter, "delay-jitter", "", "延迟随机抖动，实际延迟在 [delay - jitter, delay + jitter] 内随机（例如 20% 或 5 秒）")
+	SingleTransferCmd.Flags().Int64Var(&singleTransferSeed, "seed", 0, "延迟抖动的随机数种子，指定后延迟序列可复现 (0 表示随机)")
+	SingleTransferCmd.Flags().DurationVar(&singleTransferWaitTimeout, "wait-timeout", 0, "等待每笔交易确认的超时时间（例如 2m），超时后将交易标记为 pending/replaced/dropped 写入报告 (0 表示一直等待)")
+	SingleTransferCmd.Flags().DurationVar(&singleTransferPerWalletTimeout, "per-wallet-timeout", 0, "每个钱包估算 gas、发送和等待确认的总时间上限（例如 3m），超时后将该钱包记为失败并继续处理下一个，已发送的交易哈希仍写入报告 (0 表示不限制)")
+	SingleTransferCmd.Flags().BoolVar(&singleTransferAutoRPC, "auto-rpc", false, "未指定 --rpc 时自动探测并使用响应最快的节点")
+	SingleTransferCmd.Flags().StringVar(&singleTransferRPCNodesFile, "rpc-nodes-file", "", "--auto-rpc 探测的节点文件，格式与 check-rpc --nodes-file 相同，标注了链 ID 的节点链 ID 不一致时不会被选中（为空时探测内置 BSC 节点）")
+	SingleTransferCmd.Flags().Int64Var(&singleTransferExpectChainID, "expect-chain-id", 0, "自动选择节点时要求的链 ID (0 表示不校验)")
+	SingleTransferCmd.Flags().BoolVar(&singleTransferConfirmEach, "confirm-each", false, "每笔转账发送前显示详情并逐一确认（发送/跳过/全部中止）")
+	SingleTransferCmd.Flags().StringVar(&singleTransferReportFormat, "report-format", "csv", "转账报告格式 (csv, json, jsonl)")
+	SingleTransferCmd.Flags().BoolVar(&singleTransferOnlyFailures, "only-failures", false, "只输出失败的钱包和最终汇总，不输出每个钱包的处理进度和成功日志")
+	SingleTransferCmd.Flags().IntVar(&singleTransferRetries, "retries", 0, "每个钱包查询 nonce/余额、估算 gas 或发送交易遇到临时 RPC 错误（超时、限流、连接断开等）时的最大重试次数")
+	SingleTransferCmd.Flags().IntVar(&singleTransferMaxTotalRetries, "max-total-retries", 0, "整个运行中所有钱包重试次数的总上限，用完后中止运行 (0 表示不限制)")
+	SingleTransferCmd.Flags().IntVar(&singleTransferGasBumpPercent, "gas-bump-percent", 15, "遇到 replacement transaction underpriced / already known 时重试的 gas 价格提高百分比")
+	SingleTransferCmd.Flags().BoolVar(&singleTransferEstimateOnly, "estimate-only", false, "只获取 nonce、估算 gas 并输出每个钱包的转账计划（金额、gas、手续费、转账后余额），不广播交易")
+	SingleTransferCmd.Flags().BoolVar(&singleTransferEstimateOnly, "dry-run", false, "与 batch-transfer 的 --dry-run 对应，等同于 --estimate-only：查询 nonce、估算 gas 并输出计划，不发送任何交易")
+	SingleTransferCmd.Flags().StringVar(&singleTransferData, "data", "", "交易 data 字段的十六进制内容（例如交易所充值备注），gas 估算会包含该数据")
+	SingleTransferCmd.Flags().BoolVar(&singleTransferNoEIP155, "no-eip155", false, "使用不带链 ID 的 Homestead 签名（无重放保护），仅用于不支持 EIP-155 的私有链或旧节点（发送时报 invalid sender / unprotected 等错误）；公链上不要使用，签名的交易可在其他链上被重放")
+	SingleTransferCmd.Flags().BoolVar(&singleTransferAllowContract, "allow-contract-target", false, "允许目标地址为合约（默认检测到合约目标时中止）")
+	SingleTransferCmd.Flags().StringVar(&singleTransferSymbol, "symbol", "BNB", "日志中转账金额的单位符号")
+	SingleTransferCmd.Flags().IntVar(&singleTransferDecimals, "decimals", 18, "日志中转账金额的小数位数")
+	SingleTransferCmd.Flags().StringVar(&singleTransferDumpRaw, "dump-raw", "", "签名后立即将每笔交易的原始数据（十六进制）追加写入该文件（包括发送失败的交易和提高 gas 价格后重签的交易），可用 broadcast --raw-file 重新广播")
+	SingleTransferCmd.Flags().BoolVar(&singleTransferVerifyAfter, "verify-after", false, "全部转账完成后重新查询目标地址余额，核对余额增加量（相对发送前余额）不少于已确认转入的金额，输出未收到预期金额的地址（仅估算模式下不核对）")
+	SingleTransferCmd.Flags().BoolVar(&singleTransferPrefetch, "prefetch", false, "发送前并发预取所有钱包的余额和 nonce，减少高延迟 RPC 下的逐个查询耗时")
+	SingleTransferCmd.Flags().IntVar(&singleTransferPrefetchConcurrency, "prefetch-concurrency", 10, "预取余额和 nonce 时的最大并发请求数")
+	SingleTransferCmd.Flags().BoolVar(&singleTransferEstimateEach, "estimate-each", false, "每个钱包单独估算 gas (目标为合约地址、gas 消耗不固定时使用)")
 
 	// 设置必需参数
-	SingleTransferCmd.MarkFlagRequired("csv")
 	// SingleTransferCmd.MarkFlagRequired("target")
 }
@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// filterFundedTargets 按位置配对来源钱包和目标地址，过滤掉目标余额已达到 amount 的钱包对
+func filterFundedTargets(client *ethclient.Client, wallets []WalletInfo, targets []common.Address, amount *big.Int) ([]WalletInfo, []common.Address, error) {
+	var unfundedWallets []WalletInfo
+	var unfundedTargets []common.Address
+	for i, target := range targets {
+		balance, err := client.BalanceAt(context.Background(), target, nil)
+		if err != nil {
+			return nil, nil, fmt.Errorf("查询目标地址 %s 余额失败: %v", target.Hex(), err)
+		}
+		if balance.Cmp(amount) >= 0 {
+			continue
+		}
+		unfundedWallets = append(unfundedWallets, wallets[i])
+		unfundedTargets = append(unfundedTargets, target)
+	}
+	return unfundedWallets, unfundedTargets, nil
+}
@@ -0,0 +1,232 @@
+package cmd
+
+import (
+	"AccountSplitting/lib"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+)
+
+var (
+	snapshotRPCURL      string
+	snapshotCSVPath     string
+	snapshotOutput      string
+	snapshotAtBlock     uint64
+	snapshotConcurrency int
+	snapshotRate        float64
+	snapshotRetries     int
+)
+
+// snapshotHeader 是快照 CSV 的表头
+var snapshotHeader = []string{"address", "balance", "block"}
+
+// readSnapshotProgress 读取已有的快照文件，返回已完成的地址和快照所在区块（文件不存在时 block 为 0）。
+// 中断时可能留下不完整的最后一行，会被截断以便继续追加
+func readSnapshotProgress(path string) (map[common.Address]bool, uint64, error) {
+	done := make(map[common.Address]bool)
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return done, 0, nil
+	}
+	if err != nil {
+		return nil, 0, fmt.Errorf("读取快照文件失败: %v", err)
+	}
+	if end := bytes.LastIndexByte(data, '\n'); end+1 < len(data) {
+		data = data[:end+1]
+		if err := os.Truncate(path, int64(len(data))); err != nil {
+			return nil, 0, fmt.Errorf("截断快照文件中不完整的最后一行失败: %v", err)
+		}
+	}
+	if len(data) == 0 {
+		return done, 0, nil
+	}
+
+	records, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	if err != nil {
+		return nil, 0, fmt.Errorf("解析快照文件失败: %v", err)
+	}
+	if strings.Join(records[0], ",") != strings.Join(snapshotHeader, ",") {
+		return nil, 0, fmt.Errorf("快照文件表头应为 %s", strings.Join(snapshotHeader, ","))
+	}
+	var block uint64
+	for i, record := range records[1:] {
+		rowBlock, err := strconv.ParseUint(record[2], 10, 64)
+		if err != nil {
+			return nil, 0, fmt.Errorf("快照文件第 %d 行区块号无效: %s", i+2, record[2])
+		}
+		if block != 0 && rowBlock != block {
+			return nil, 0, fmt.Errorf("快照文件包含不同区块的余额 (%d 和 %d)", block, rowBlock)
+		}
+		block = rowBlock
+		done[common.HexToAddress(record[0])] = true
+	}
+	return done, block, nil
+}
+
+// SnapshotCmd 是在固定区块上批量记录钱包余额的命令
+var SnapshotCmd = &cobra.Command{
+	Use:   "snapshot",
+	Short: "在同一区块上记录 CSV 中所有钱包的余额，支持限速和中断后继续",
+	Long:  `读取 CSV 中的地址，以限定的并发数和请求速率查询所有地址在 --at-block 区块的余额，每查询完一个地址立即追加一行 address,balance,block 到输出 CSV。输出文件同时记录进度：中断后重新运行同一命令会跳过已记录的地址，并沿用文件中的区块号保证整份快照来自同一区块。未指定 --at-block 时使用开始时的最新区块；查询较早的区块需要归档节点。`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if snapshotCSVPath == "" {
+			log.Fatal("请提供钱包 CSV 文件路径 (--csv)")
+		}
+		if snapshotConcurrency <= 0 {
+			log.Fatal("并发数必须大于 0 (--concurrency)")
+		}
+		if snapshotRate < 0 {
+			log.Fatal("请求速率不能为负数 (--rate)")
+		}
+		if snapshotRetries < 0 {
+			log.Fatal("重试次数不能为负数 (--retries)")
+		}
+
+		addresses, err := readAddressesFromCSV(snapshotCSVPath)
+		if err != nil {
+			log.Fatalf("读取钱包 CSV 文件失败: %v", err)
+		}
+
+		output := snapshotOutput
+		if output == "" {
+			output = strings.TrimSuffix(snapshotCSVPath, filepath.Ext(snapshotCSVPath)) + "_snapshot.csv"
+		}
+		done, fileBlock, err := readSnapshotProgress(output)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if fileBlock != 0 && snapshotAtBlock != 0 && fileBlock != snapshotAtBlock {
+			log.Fatalf("已有快照 %s 的区块为 %d，与 --at-block %d 不一致，请换一个输出文件", output, fileBlock, snapshotAtBlock)
+		}
+
+		var pending []common.Address
+		seen := make(map[common.Address]bool)
+		for _, addr := range addresses {
+			if done[addr] || seen[addr] {
+				continue
+			}
+			seen[addr] = true
+			pending = append(pending, addr)
+		}
+		if len(done) > 0 {
+			log.Printf("已有快照 %s 记录了 %d 个地址，将继续查询剩余 %d 个", output, len(done), len(pending))
+		}
+		if len(pending) == 0 {
+			log.Printf("所有地址均已记录，无需查询")
+			return
+		}
+
+		client, err := dialClient(context.Background(), snapshotRPCURL)
+		if err != nil {
+			log.Fatalf("连接以太坊网络失败: %v", err)
+		}
+
+		// 整份快照固定在同一区块：优先沿用已有文件的区块，其次 --at-block，最后取当前最新区块
+		block := fileBlock
+		if block == 0 {
+			block = snapshotAtBlock
+		}
+		if block == 0 {
+			block, err = client.BlockNumber(context.Background())
+			if err != nil {
+				log.Fatalf("获取最新区块号失败: %v", err)
+			}
+		}
+		blockNumber := new(big.Int).SetUint64(block)
+		log.Printf("在区块 %d 上查询 %d 个地址的余额（并发数 %d）", block, len(pending), snapshotConcurrency)
+
+		if dir := filepath.Dir(output); dir != "." {
+			if err := os.MkdirAll(dir, 0755); err != nil {
+				log.Fatalf("创建输出目录失败: %v", err)
+			}
+		}
+		file, err := os.OpenFile(output, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+		if err != nil {
+			log.Fatalf("打开快照文件失败: %v", err)
+		}
+		defer file.Close()
+		writer := csv.NewWriter(file)
+		if info, err := file.Stat(); err == nil && info.Size() == 0 {
+			writer.Write(snapshotHeader)
+			writer.Flush()
+		}
+
+		// 限速：每个请求前从 ticker 领取一个令牌
+		var limiter <-chan time.Time
+		if snapshotRate > 0 {
+			ticker := time.NewTicker(time.Duration(float64(time.Second) / snapshotRate))
+			defer ticker.Stop()
+			limiter = ticker.C
+		}
+
+		var mu sync.Mutex
+		var wg sync.WaitGroup
+		sem := make(chan struct{}, snapshotConcurrency)
+		progress := lib.NewProgress(len(pending))
+		failed := 0
+		for _, addr := range pending {
+			wg.Add(1)
+			sem <- struct{}{}
+			go func(addr common.Address) {
+				defer wg.Done()
+				defer func() { <-sem }()
+
+				var balance *big.Int
+				err := withRetries(context.Background(), "查询 "+addr.Hex()+" 的余额", snapshotRetries, newRetryBudget(0), func() error {
+					if limiter != nil {
+						<-limiter
+					}
+					var err error
+					balance, err = client.BalanceAt(context.Background(), addr, blockNumber)
+					return err
+				})
+
+				mu.Lock()
+				defer mu.Unlock()
+				progress.Add(1)
+				if err != nil {
+					failed++
+					log.Printf("查询 %s 的余额失败: %v", addr.Hex(), err)
+					return
+				}
+				writer.Write([]string{addr.Hex(), balance.String(), strconv.FormatUint(block, 10)})
+				writer.Flush()
+				if err := writer.Error(); err != nil {
+					log.Fatalf("写入快照文件失败: %v", err)
+				}
+			}(addr)
+		}
+		wg.Wait()
+		progress.Finish()
+
+		if failed > 0 {
+			log.Printf("快照未完成：%d 个地址查询失败，重新运行同一命令将只查询这些地址", failed)
+			return
+		}
+		log.Printf("快照完成！区块 %d，共 %d 个地址，已写入 %s", block, len(done)+len(pending), output)
+	},
+}
+
+func init() {
+	SnapshotCmd.Flags().StringVar(&snapshotRPCURL, "rpc", "https://bsc-dataseed.binance.org/", "以太坊 RPC URL")
+	SnapshotCmd.Flags().StringVar(&snapshotCSVPath, "csv", "", "钱包 CSV 文件路径（只需要地址列）")
+	SnapshotCmd.Flags().StringVarP(&snapshotOutput, "output", "o", "", "快照输出 CSV 文件路径，已存在时从中断处继续（为空时使用 <csv>_snapshot.csv）")
+	SnapshotCmd.Flags().Uint64Var(&snapshotAtBlock, "at-block", 0, "查询余额的区块号，所有地址均在该区块上查询 (0 表示开始时的最新区块)")
+	SnapshotCmd.Flags().IntVar(&snapshotConcurrency, "concurrency", 10, "查询余额的最大并发请求数")
+	SnapshotCmd.Flags().Float64Var(&snapshotRate, "rate", 0, "每秒最多发送的查询请求数，用于避免触发节点限流 (0 表示不限速)")
+	SnapshotCmd.Flags().IntVar(&snapshotRetries, "retries", 2, "每个地址遇到临时 RPC 错误时的最大重试次数")
+
+	SnapshotCmd.MarkFlagRequired("csv")
+}
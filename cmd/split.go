@@ -0,0 +1,218 @@
+package cmd
+
+import (
+	"AccountSplitting/lib"
+	"context"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/spf13/cobra"
+)
+
+var (
+	splitNumber        int
+	splitDir           string
+	splitOutput        string
+	splitTotal         string
+	splitRPCURL        string
+	splitContract      string
+	splitSenderCSV     string
+	splitSenderIndex   int
+	splitGasMultiplier float64
+	splitBatchSize     int
+	splitDrainDust     bool   // 分配完成后把不能整除的零头转给最后一个钱包或 --dust-to
+	splitDustTo        string // 零头接收地址（为空时使用最后一个新钱包）
+)
+
+// SplitCmd 是生成新钱包并把总金额平均分配给它们的命令
+var SplitCmd = &cobra.Command{
+	Use:   "split",
+	Short: "生成 N 个新钱包并把总金额平均转入，一步完成账户拆分",
+	Long:  `生成 --number 个带助记词的新钱包并写入 CSV，然后由发送者钱包通过批量转账合约把 --total 平均分配给这些钱包。不能整除的零头默认留在发送者钱包中，指定 --drain-dust 时在最后一批之后把零头单独转给最后一个新钱包或 --dust-to，使 --total 被完整分配。完成后输出 CSV 路径、每个钱包的金额和所有交易哈希。`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if splitNumber <= 0 {
+			log.Fatal("钱包数量必须大于 0 (--number)")
+		}
+		if splitBatchSize <= 0 {
+			log.Fatal("批次大小必须大于 0 (--batch-size)")
+		}
+		if splitContract != "" && !common.IsHexAddress(splitContract) {
+			log.Fatalf("无效的合约地址 (--contract): %s", splitContract)
+		}
+		if splitDustTo != "" && !splitDrainDust {
+			log.Fatal("--dust-to 需要与 --drain-dust 一起使用")
+		}
+		if splitDustTo != "" && !common.IsHexAddress(splitDustTo) {
+			log.Fatalf("无效的零头接收地址 (--dust-to): %s", splitDustTo)
+		}
+		total, err := parseAmount(splitTotal)
+		if err != nil {
+			log.Fatalf("总金额无效 (--total): %v", err)
+		}
+		perWallet := new(big.Int).Div(total, big.NewInt(int64(splitNumber)))
+		if perWallet.Sign() <= 0 {
+			log.Fatalf("总金额 %s 不足以分配给 %d 个钱包", formatEther(total), splitNumber)
+		}
+		remainder := new(big.Int).Sub(total, new(big.Int).Mul(perWallet, big.NewInt(int64(splitNumber))))
+
+		senderWallets, err := readWalletsFromCSV(splitSenderCSV)
+		if err != nil {
+			log.Fatalf("读取发送者钱包 CSV 文件失败: %v", err)
+		}
+		if splitSenderIndex < 0 || splitSenderIndex >= len(senderWallets) {
+			log.Fatalf("发送者钱包索引超出范围 (0-%d)", len(senderWallets)-1)
+		}
+
+		output := splitOutput
+		if output == "" {
+			output = fmt.Sprintf("split_%s.csv", time.Now().Format("20060102_150405"))
+		}
+		csvPath := filepath.Join(splitDir, output)
+		if _, err := os.Stat(csvPath); err == nil {
+			log.Fatalf("输出文件已存在，为避免覆盖已有钱包已中止: %s", csvPath)
+		}
+
+		client, err := dialClient(context.Background(), splitRPCURL)
+		if err != nil {
+			log.Fatalf("连接以太坊网络失败: %v", err)
+		}
+		// 未指定 --contract 时按所连接网络的链 ID 选择默认合约
+		if splitContract == "" {
+			splitContract, err = defaultSplitterContract(client)
+			if err != nil {
+				log.Fatal(err)
+			}
+		}
+		suggestedGasPrice, err := client.SuggestGasPrice(context.Background())
+		if err != nil {
+			log.Fatalf("获取网络 gas 价格失败: %v", err)
+		}
+		gasPriceWei := new(big.Int).Mul(suggestedGasPrice, big.NewInt(int64(splitGasMultiplier*100)))
+		gasPriceWei.Div(gasPriceWei, big.NewInt(100))
+
+		// 1. 生成钱包
+		if err := os.MkdirAll(splitDir, 0755); err != nil {
+			log.Fatalf("创建目录失败: %v", err)
+		}
+		if err := lib.GmwsAndWirte(splitNumber, csvPath, lib.GenOptions{Format: "csv", Strength: 128}); err != nil {
+			log.Fatalf("生成钱包失败: %v", err)
+		}
+		log.Printf("已生成 %d 个钱包: %s", splitNumber, csvPath)
+
+		// 2. 平均分配
+		dustNote := "留在发送者钱包"
+		if splitDrainDust {
+			dustNote = "将在最后单独转出"
+		}
+		log.Printf("由 %s 向 %d 个钱包各转入 %s（总额 %s，零头 %s %s）",
+			senderWallets[splitSenderIndex].Address, splitNumber, formatEther(perWallet), formatEther(total), formatEther(remainder), dustNote)
+		cfg := &Config{
+			RPCURL:          splitRPCURL,
+			ContractAddress: splitContract,
+			CSVFilePaths:    []string{csvPath},
+			AmountPerWallet: perWallet,
+			GasPrice:        gasPriceWei,
+			BatchSize:       splitBatchSize,
+			SenderWallet:    senderWallets[splitSenderIndex],
+			ReportFormat:    "csv",
+			GasBuffer:       defaultGasBuffer,
+		}
+		summary, err := ExecuteBatchTransfer(cfg)
+
+		log.Printf("\n拆分结果:")
+		log.Printf("- 钱包 CSV: %s", csvPath)
+		log.Printf("- 每个钱包金额: %s", formatEther(perWallet))
+		log.Printf("- 成功批次: %d/%d", summary.SuccessBatches, summary.TotalBatches)
+		for _, hash := range summary.TxHashes {
+			log.Printf("- 交易哈希: %s", hash)
+		}
+		if err != nil {
+			log.Fatalf("分配失败，钱包已生成但可能未全部到账: %v", err)
+		}
+
+		// 3. 把总额减去实际分配金额后的零头转出
+		if splitDrainDust {
+			if len(summary.FailedBatches) > 0 {
+				log.Printf("有 %d 批分配失败，未转出零头 %s，请在补发失败批次后手动转出", len(summary.FailedBatches), formatEther(remainder))
+			} else {
+				distributed := new(big.Int).Mul(perWallet, big.NewInt(int64(summary.TotalWallets)))
+				dust := new(big.Int).Sub(total, distributed)
+				if err := drainSplitDust(client, senderWallets[splitSenderIndex], csvPath, dust, gasPriceWei); err != nil {
+					log.Fatalf("转出零头 %s 失败，%d 个钱包已分配完成，请手动转出: %v", formatEther(dust), summary.TotalWallets, err)
+				}
+			}
+		}
+		log.Printf("拆分完成！")
+	},
+}
+
+// drainSplitDust 把零头从发送者钱包转给 --dust-to，未指定时转给 csvPath 中的最后一个钱包
+func drainSplitDust(client *ethclient.Client, sender WalletInfo, csvPath string, dust, gasPriceWei *big.Int) error {
+	if dust.Sign() <= 0 {
+		log.Printf("总额已被完整分配，没有零头需要转出")
+		return nil
+	}
+	// 零头不足以支付单独转账的手续费时转出反而亏损，留在发送者钱包
+	fee := new(big.Int).Mul(gasPriceWei, new(big.Int).SetUint64(relayTransferGas))
+	if dust.Cmp(fee) <= 0 {
+		log.Printf("零头 %s 不超过单独转账的手续费 %s，不转出，留在发送者钱包", formatEther(dust), formatEther(fee))
+		return nil
+	}
+	to := common.HexToAddress(splitDustTo)
+	if splitDustTo == "" {
+		wallets, err := readWalletsFromCSV(csvPath)
+		if err != nil {
+			return fmt.Errorf("读取新钱包 CSV 文件失败: %v", err)
+		}
+		to = common.HexToAddress(wallets[len(wallets)-1].Address)
+	}
+	privateKey, err := crypto.HexToECDSA(strings.TrimPrefix(sender.PrivateKey, "0x"))
+	if err != nil {
+		return fmt.Errorf("解析发送者私钥失败: %v", err)
+	}
+	chainID, err := client.ChainID(context.Background())
+	if err != nil {
+		return fmt.Errorf("获取链 ID 失败: %v", err)
+	}
+	tx, err := sendNative(client, chainID, privateKey, to, dust, gasPriceWei)
+	if err != nil {
+		return err
+	}
+	log.Printf("转出零头 %s 到 %s，交易哈希: %s", formatEther(dust), to.Hex(), tx.Hash().Hex())
+	receipt, err := bind.WaitMined(context.Background(), client, tx)
+	if err != nil {
+		return fmt.Errorf("等待零头交易确认失败: %v", err)
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return fmt.Errorf("零头交易执行失败: %s", tx.Hash().Hex())
+	}
+	log.Printf("零头 %s 已转入 %s", formatEther(dust), to.Hex())
+	return nil
+}
+
+func init() {
+	SplitCmd.Flags().IntVarP(&splitNumber, "number", "n", 10, "生成钱包数量")
+	SplitCmd.Flags().StringVarP(&splitDir, "dir", "d", "./wallets", "钱包 CSV 输出目录")
+	SplitCmd.Flags().StringVarP(&splitOutput, "output", "o", "", "钱包 CSV 文件名（为空时使用 split_时间戳.csv）")
+	SplitCmd.Flags().StringVar(&splitTotal, "total", "", "分配的原生币总金额，支持 wei/gwei/ether 单位后缀")
+	SplitCmd.Flags().StringVar(&splitRPCURL, "rpc", "https://bsc-dataseed.binance.org/", "以太坊 RPC URL")
+	SplitCmd.Flags().StringVar(&splitContract, "contract", "", "批量转账合约地址（为空时按链 ID 使用已知的默认合约）")
+	SplitCmd.Flags().StringVar(&splitSenderCSV, "sender-csv", "wallets/senders/w1.csv", "发送者钱包 CSV 文件路径")
+	SplitCmd.Flags().IntVar(&splitSenderIndex, "sender-index", 0, "发送者钱包在 CSV 中的索引")
+	SplitCmd.Flags().Float64Var(&splitGasMultiplier, "gas-multiplier", 1.0001, "Gas 价格倍率 (相对于网络平均 gas 价格)")
+	SplitCmd.Flags().IntVar(&splitBatchSize, "batch-size", defaultBatchSize, "每批处理的钱包数量")
+	SplitCmd.Flags().BoolVar(&splitDrainDust, "drain-dust", false, "最后一批完成后，把 --total 减去实际分配总额的零头单独转给最后一个新钱包（或 --dust-to），使总额被完整分配；零头不超过单独转账的手续费时不转出")
+	SplitCmd.Flags().StringVar(&splitDustTo, "dust-to", "", "--drain-dust 时零头的接收地址（普通地址，为空时使用最后一个新钱包）")
+
+	SplitCmd.MarkFlagRequired("total")
+}
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"math/big"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	sumCSVPath string
+	sumAmount  string
+)
+
+// SumCmd 是统计分账总额的命令
+var SumCmd = &cobra.Command{
+	Use:   "sum",
+	Short: "统计接收者 CSV 中待分发的总金额",
+	Long:  `读取接收者 CSV（使用可选的 Amount 列或统一的 --amount），输出接收者数量和待分发总额（Wei 和 ETH），不需要连接网络。`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if sumCSVPath == "" {
+			log.Fatal("请提供接收者钱包 CSV 文件路径 (--csv)")
+		}
+
+		var flatAmount *big.Int
+		if sumAmount != "" {
+			amount, err := parseAmount(sumAmount)
+			if err != nil {
+				log.Fatalf("转账金额无效 (--amount): %v", err)
+			}
+			flatAmount = amount
+		}
+
+		wallets, err := readWalletsFromCSV(sumCSVPath)
+		if err != nil {
+			log.Fatalf("读取接收者钱包 CSV 文件失败: %v", err)
+		}
+		recipients, err := recipientsFromWallets(wallets, flatAmount)
+		if err != nil {
+			log.Fatalf("计算接收者金额失败: %v (未设置 Amount 列的行需要通过 --amount 指定金额)", err)
+		}
+
+		total := sumRecipientAmounts(recipients)
+		fmt.Printf("接收者数量: %d\n", len(recipients))
+		fmt.Printf("分发总额: %s Wei\n", total.String())
+		fmt.Printf("分发总额: %s ETH\n", formatEther(total))
+	},
+}
+
+func init() {
+	SumCmd.Flags().StringVar(&sumCSVPath, "csv", "", "接收者钱包 CSV 文件路径")
+	SumCmd.Flags().StringVar(&sumAmount, "amount", "", "统一的每个钱包金额 (ETH)，用于没有 Amount 列或该列为空的行")
+
+	SumCmd.MarkFlagRequired("csv")
+}
@@ -0,0 +1,304 @@
+package cmd
+
+import (
+	"context"
+	"log"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/spf13/cobra"
+)
+
+var (
+	sweepTokenRPCURL              string
+	sweepTokenCSVPath             string
+	sweepTokenAddress             string
+	sweepTokenTarget              string
+	sweepTokenGasMultiplier       float64
+	sweepTokenMaxWallets          int
+	sweepTokenFundGas             bool
+	sweepTokenSenderCSV           string
+	sweepTokenSenderIndex         int
+	sweepTokenReportFormat        string
+	sweepTokenAbortOnInsufficient bool
+)
+
+// sweepCandidate 是持有代币、等待归集的钱包
+type sweepCandidate struct {
+	Wallet   WalletInfo
+	Address  common.Address
+	Balance  *big.Int // 代币余额（最小单位）
+	GasLimit uint64
+	Shortage *big.Int // 原生币不足以支付手续费的差额，nil 表示足够
+}
+
+// SweepTokenCmd 是归集 ERC-20 代币的命令
+var SweepTokenCmd = &cobra.Command{
+	Use:   "sweep-token",
+	Short: "将 CSV 中每个钱包的 ERC-20 代币全部转入目标地址",
+	Long:  `读取钱包 CSV，查询每个钱包在 --token 合约中的余额，余额不为 0 时通过 transfer 把全部代币转入 --target。原生币不足以支付 gas 的钱包默认跳过并说明原因，指定 --fund-gas 时先由发送者钱包补足差额再归集。`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if sweepTokenCSVPath == "" {
+			log.Fatal("请提供钱包 CSV 文件路径 (--csv)")
+		}
+		if !common.IsHexAddress(sweepTokenAddress) {
+			log.Fatalf("无效的代币合约地址 (--token): %s", sweepTokenAddress)
+		}
+		if !common.IsHexAddress(sweepTokenTarget) {
+			log.Fatalf("无效的目标地址 (--target): %s", sweepTokenTarget)
+		}
+		if err := validateReportFormat(sweepTokenReportFormat); err != nil {
+			log.Fatal(err)
+		}
+		tokenAddress := common.HexToAddress(sweepTokenAddress)
+		target := common.HexToAddress(sweepTokenTarget)
+
+		wallets, err := readWalletsFromCSV(sweepTokenCSVPath)
+		if err != nil {
+			log.Fatalf("读取钱包 CSV 文件失败: %v", err)
+		}
+		if sweepTokenMaxWallets > 0 && len(wallets) > sweepTokenMaxWallets {
+			wallets = wallets[:sweepTokenMaxWallets]
+		}
+
+		ctx := context.Background()
+		client, err := dialClient(ctx, sweepTokenRPCURL)
+		if err != nil {
+			log.Fatalf("连接以太坊网络失败: %v", err)
+		}
+		chainID, err := client.ChainID(ctx)
+		if err != nil {
+			log.Fatalf("获取链 ID 失败: %v", err)
+		}
+		parsedABI, err := abi.JSON(strings.NewReader(erc20ABI))
+		if err != nil {
+			log.Fatalf("解析 ERC-20 ABI 失败: %v", err)
+		}
+		token := bind.NewBoundContract(tokenAddress, parsedABI, client, client, client)
+
+		// 代币精度和符号用于展示，读取失败时按 18 位小数处理
+		unit := DisplayUnit{Symbol: "代币", Decimals: 18}
+		var out []interface{}
+		if err := token.Call(&bind.CallOpts{}, &out, "decimals"); err == nil && len(out) == 1 {
+			unit.Decimals = int(out[0].(uint8))
+		}
+		out = nil
+		if err := token.Call(&bind.CallOpts{}, &out, "symbol"); err == nil && len(out) == 1 && out[0].(string) != "" {
+			unit.Symbol = out[0].(string)
+		}
+
+		suggestedGasPrice, err := client.SuggestGasPrice(ctx)
+		if err != nil {
+			log.Fatalf("获取网络 gas 价格失败: %v", err)
+		}
+		gasPriceWei := new(big.Int).Mul(suggestedGasPrice, big.NewInt(int64(sweepTokenGasMultiplier*10000)))
+		gasPriceWei.Div(gasPriceWei, big.NewInt(10000))
+
+		log.Printf("配置信息:")
+		log.Printf("- 代币合约: %s (%s，%d 位小数)", tokenAddress.Hex(), unit.Symbol, unit.Decimals)
+		log.Printf("- 目标地址: %s", target.Hex())
+		log.Printf("- Gas 价格: %.4f Gwei", float64(gasPriceWei.Int64())/1e9)
+		log.Printf("- 钱包数量: %d", len(wallets))
+
+		// 查询代币余额并估算每个钱包的 gas，找出原生币不足的钱包
+		var candidates []*sweepCandidate
+		var needGas []*sweepCandidate
+		var skipped []string
+		for i, wallet := range wallets {
+			if !common.IsHexAddress(wallet.Address) {
+				log.Printf("[%d/%d] 地址无效，跳过: %s", i+1, len(wallets), wallet.Address)
+				skipped = append(skipped, wallet.Address+": 地址无效")
+				continue
+			}
+			addr := common.HexToAddress(wallet.Address)
+			out = nil
+			if err := token.Call(&bind.CallOpts{}, &out, "balanceOf", addr); err != nil || len(out) != 1 {
+				log.Printf("[%d/%d] %s 查询代币余额失败，跳过: %v", i+1, len(wallets), addr.Hex(), err)
+				skipped = append(skipped, addr.Hex()+": 查询代币余额失败")
+				continue
+			}
+			balance := out[0].(*big.Int)
+			if balance.Sign() == 0 {
+				continue
+			}
+
+			data, err := parsedABI.Pack("transfer", target, balance)
+			if err != nil {
+				log.Fatalf("打包 transfer 调用数据失败: %v", err)
+			}
+			gasLimit, err := client.EstimateGas(ctx, ethereum.CallMsg{From: addr, To: &tokenAddress, Data: data})
+			if err != nil {
+				log.Printf("[%d/%d] %s 估算 gas 失败，跳过: %v", i+1, len(wallets), addr.Hex(), err)
+				skipped = append(skipped, addr.Hex()+": 估算 gas 失败: "+revertReasonFromError(err))
+				continue
+			}
+			gasLimit = gasLimit * 12 / 10 // 增加 20% 的缓冲
+
+			candidate := &sweepCandidate{Wallet: wallet, Address: addr, Balance: balance, GasLimit: gasLimit}
+			nativeBalance, err := client.BalanceAt(ctx, addr, nil)
+			if err != nil {
+				log.Printf("[%d/%d] %s 查询原生币余额失败，跳过: %v", i+1, len(wallets), addr.Hex(), err)
+				skipped = append(skipped, addr.Hex()+": 查询原生币余额失败")
+				continue
+			}
+			fee := new(big.Int).Mul(new(big.Int).SetUint64(gasLimit), gasPriceWei)
+			if nativeBalance.Cmp(fee) < 0 {
+				candidate.Shortage = new(big.Int).Sub(fee, nativeBalance)
+				needGas = append(needGas, candidate)
+			}
+			candidates = append(candidates, candidate)
+		}
+		log.Printf("共 %d 个钱包持有代币，其中 %d 个原生币不足以支付 gas", len(candidates), len(needGas))
+		if sweepTokenAbortOnInsufficient && len(needGas) > 0 && !sweepTokenFundGas {
+			log.Fatalf("钱包 %s 原生币余额不足以支付 gas（需要补充 %s），已在归集前中止 (--abort-on-insufficient)",
+				needGas[0].Address.Hex(), formatEther(needGas[0].Shortage))
+		}
+
+		// 为原生币不足的钱包补充 gas，或跳过并说明原因
+		funded := make(map[common.Address]bool)
+		if len(needGas) > 0 && sweepTokenFundGas {
+			senderWallets, err := readWalletsFromCSV(sweepTokenSenderCSV)
+			if err != nil {
+				log.Fatalf("读取发送者钱包 CSV 文件失败: %v", err)
+			}
+			if sweepTokenSenderIndex < 0 || sweepTokenSenderIndex >= len(senderWallets) {
+				log.Fatalf("发送者钱包索引超出范围 (0-%d)", len(senderWallets)-1)
+			}
+			senderKey, err := crypto.HexToECDSA(strings.TrimPrefix(senderWallets[sweepTokenSenderIndex].PrivateKey, "0x"))
+			if err != nil {
+				log.Fatalf("解析发送者私钥失败: %v", err)
+			}
+			var targets []common.Address
+			var amounts []*big.Int
+			for _, candidate := range needGas {
+				targets = append(targets, candidate.Address)
+				amounts = append(amounts, candidate.Shortage)
+			}
+			log.Printf("由 %s 为 %d 个钱包补充 gas...", crypto.PubkeyToAddress(senderKey.PublicKey).Hex(), len(targets))
+			fundedAddrs, spent := sendGasFunding(client, chainID, senderKey, targets, amounts, gasPriceWei)
+			for _, addr := range fundedAddrs {
+				funded[addr] = true
+			}
+			log.Printf("补充 gas 完成，成功 %d 个，花费 %s", len(fundedAddrs), formatEther(spent))
+		}
+
+		// 逐个归集代币
+		reportPath := resultFilePath(sweepTokenCSVPath, "_sweep_res", sweepTokenReportFormat)
+		signer := types.NewEIP155Signer(chainID)
+		totalSwept := new(big.Int)
+		successCount, failCount := 0, 0
+		for i, candidate := range candidates {
+			if candidate.Shortage != nil && !funded[candidate.Address] {
+				reason := "原生币余额不足以支付 gas，需要补充 " + formatEther(candidate.Shortage)
+				if sweepTokenFundGas {
+					reason = "补充 gas 失败"
+				}
+				if sweepTokenAbortOnInsufficient {
+					log.Fatalf("[%d/%d] %s %s，已中止归集 (--abort-on-insufficient)，已成功归集 %d 个钱包", i+1, len(candidates), candidate.Address.Hex(), reason, successCount)
+				}
+				log.Printf("[%d/%d] %s %s，跳过", i+1, len(candidates), candidate.Address.Hex(), reason)
+				skipped = append(skipped, candidate.Address.Hex()+": "+reason)
+				continue
+			}
+
+			result := TransferResult{
+				Address: candidate.Address.Hex(),
+				Target:  target.Hex(),
+				Amount:  candidate.Balance.String(),
+			}
+			recordResult := func() {
+				if err := appendResult(result, reportPath, sweepTokenReportFormat); err != nil {
+					log.Printf("写入结果文件失败: %v", err)
+				}
+			}
+
+			privateKey, err := crypto.HexToECDSA(strings.TrimPrefix(candidate.Wallet.PrivateKey, "0x"))
+			if err != nil {
+				log.Printf("[%d/%d] %s 解析私钥失败: %v", i+1, len(candidates), candidate.Address.Hex(), err)
+				result.Error = "解析私钥失败"
+				recordResult()
+				failCount++
+				continue
+			}
+			nonce, err := client.PendingNonceAt(ctx, candidate.Address)
+			if err != nil {
+				log.Printf("[%d/%d] %s 获取 nonce 失败: %v", i+1, len(candidates), candidate.Address.Hex(), err)
+				result.Error = "获取nonce失败"
+				recordResult()
+				failCount++
+				continue
+			}
+			data, _ := parsedABI.Pack("transfer", target, candidate.Balance)
+			tx := types.NewTransaction(nonce, tokenAddress, big.NewInt(0), candidate.GasLimit, gasPriceWei, data)
+			signedTx, err := types.SignTx(tx, signer, privateKey)
+			if err != nil {
+				log.Printf("[%d/%d] %s 签名交易失败: %v", i+1, len(candidates), candidate.Address.Hex(), err)
+				result.Error = "签名交易失败"
+				recordResult()
+				failCount++
+				continue
+			}
+			if err := client.SendTransaction(ctx, signedTx); err != nil {
+				log.Printf("[%d/%d] %s 发送交易失败: %v", i+1, len(candidates), candidate.Address.Hex(), err)
+				result.Error = "发送交易失败"
+				recordResult()
+				failCount++
+				continue
+			}
+			result.TxHash = signedTx.Hash().Hex()
+			receipt, err := bind.WaitMined(ctx, client, signedTx)
+			if err != nil {
+				log.Printf("[%d/%d] %s 等待交易确认失败: %v", i+1, len(candidates), candidate.Address.Hex(), err)
+				result.Error = "等待交易确认失败"
+				recordResult()
+				failCount++
+				continue
+			}
+			result.GasUsed = receipt.GasUsed
+			if receipt.Status != types.ReceiptStatusSuccessful {
+				log.Printf("[%d/%d] %s 归集交易执行失败: %s", i+1, len(candidates), candidate.Address.Hex(), result.TxHash)
+				result.Error = "交易执行失败"
+				recordResult()
+				failCount++
+				continue
+			}
+			result.IsSuccess = true
+			recordResult()
+			totalSwept.Add(totalSwept, candidate.Balance)
+			successCount++
+			log.Printf("[%d/%d] 已归集 %s 的 %s，交易哈希: %s", i+1, len(candidates), candidate.Address.Hex(), unit.Format(candidate.Balance), result.TxHash)
+		}
+
+		log.Printf("\n归集完成！成功: %d，失败: %d，跳过: %d，共归集 %s", successCount, failCount, len(skipped), unit.Format(totalSwept))
+		if len(skipped) > 0 {
+			log.Printf("跳过的钱包:")
+			for _, item := range skipped {
+				log.Printf("- %s", item)
+			}
+		}
+	},
+}
+
+func init() {
+	SweepTokenCmd.Flags().StringVar(&sweepTokenRPCURL, "rpc", "https://bsc-dataseed.binance.org/", "以太坊 RPC URL")
+	SweepTokenCmd.Flags().StringVar(&sweepTokenCSVPath, "csv", "", "钱包 CSV 文件路径")
+	SweepTokenCmd.Flags().StringVar(&sweepTokenAddress, "token", "", "ERC-20 代币合约地址")
+	SweepTokenCmd.Flags().StringVar(&sweepTokenTarget, "target", "", "代币归集的目标地址")
+	SweepTokenCmd.Flags().Float64Var(&sweepTokenGasMultiplier, "gas-multiplier", 1.0001, "Gas 价格倍率 (相对于网络平均 gas 价格)")
+	SweepTokenCmd.Flags().IntVar(&sweepTokenMaxWallets, "max-wallets", 0, "最大处理钱包数量 (0 表示不限制)")
+	SweepTokenCmd.Flags().BoolVar(&sweepTokenFundGas, "fund-gas", false, "原生币不足以支付 gas 时，先由发送者钱包补足差额再归集（默认跳过这些钱包）")
+	SweepTokenCmd.Flags().BoolVar(&sweepTokenAbortOnInsufficient, "abort-on-insufficient", false, "有钱包原生币不足以支付 gas（且未通过 --fund-gas 补足）时中止整个归集，而不是跳过")
+	SweepTokenCmd.Flags().StringVar(&sweepTokenSenderCSV, "sender-csv", "wallets/senders/w1.csv", "--fund-gas 使用的发送者钱包 CSV 文件路径")
+	SweepTokenCmd.Flags().IntVar(&sweepTokenSenderIndex, "sender-index", 0, "--fund-gas 使用的发送者钱包在 CSV 中的索引")
+	SweepTokenCmd.Flags().StringVar(&sweepTokenReportFormat, "report-format", "csv", "归集报告格式 (csv, json, jsonl)")
+
+	SweepTokenCmd.MarkFlagRequired("csv")
+	SweepTokenCmd.MarkFlagRequired("token")
+	SweepTokenCmd.MarkFlagRequired("target")
+}
@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"AccountSplitting/lib"
+	"context"
+	"fmt"
+	"log"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// erc20ABI 是分发和归集代币所需的 ERC-20 函数定义
+const erc20ABI = `[{"constant":true,"inputs":[{"name":"owner","type":"address"},{"name":"spender","type":"address"}],"name":"allowance","outputs":[{"name":"","type":"uint256"}],"type":"function"},{"constant":false,"inputs":[{"name":"spender","type":"address"},{"name":"value","type":"uint256"}],"name":"approve","outputs":[{"name":"","type":"bool"}],"type":"function"},{"constant":true,"inputs":[{"name":"owner","type":"address"}],"name":"balanceOf","outputs":[{"name":"","type":"uint256"}],"type":"function"},{"constant":true,"inputs":[],"name":"decimals","outputs":[{"name":"","type":"uint8"}],"type":"function"},{"constant":true,"inputs":[],"name":"symbol","outputs":[{"name":"","type":"string"}],"type":"function"},{"constant":false,"inputs":[{"name":"to","type":"address"},{"name":"value","type":"uint256"}],"name":"transfer","outputs":[{"name":"","type":"bool"}],"type":"function"}]`
+
+// readTokenDecimals 查询代币的小数位数
+func readTokenDecimals(client *ethclient.Client, token common.Address) (int, error) {
+	parsedABI, err := abi.JSON(strings.NewReader(erc20ABI))
+	if err != nil {
+		return 0, fmt.Errorf("解析 ERC-20 ABI 失败: %v", err)
+	}
+	contract := bind.NewBoundContract(token, parsedABI, client, client, client)
+
+	var out []interface{}
+	if err := contract.Call(&bind.CallOpts{}, &out, "decimals"); err != nil || len(out) != 1 {
+		return 0, fmt.Errorf("查询代币 %s 的小数位数失败: %v", token.Hex(), err)
+	}
+	return int(out[0].(uint8)), nil
+}
+
+// scaleToTokenUnits 将按 18 位小数解析的金额换算为 decimals 位小数代币的最小单位，
+// 金额精度超过代币的小数位数时返回错误而不是截断
+func scaleToTokenUnits(amount *big.Int, decimals int) (*big.Int, error) {
+	if decimals >= 18 {
+		return new(big.Int).Mul(amount, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals-18)), nil)), nil
+	}
+	divisor := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(18-decimals)), nil)
+	scaled, remainder := new(big.Int).QuoRem(amount, divisor, new(big.Int))
+	if remainder.Sign() != 0 {
+		return nil, fmt.Errorf("金额 %s 超过代币的 %d 位小数精度", formatUnits(amount, 18), decimals)
+	}
+	return scaled, nil
+}
+
+// ensureTokenAllowance 查询 auth.From 对 spender 的代币授权额度，额度不少于 required 时不发送 approve；
+// 否则授权 required（infinite 为 true 时授权 uint256 最大值，之后的运行不再需要 approve）并等待确认。
+// 已有非零额度时先授权为 0，兼容 USDT 等不允许直接修改非零额度的代币。
+// nonces 不为 nil 时 approve 交易从中取 nonce，否则由节点自动获取
+func ensureTokenAllowance(client *ethclient.Client, auth *bind.TransactOpts, token, spender common.Address, required *big.Int, infinite bool, nonces *lib.NonceManager, unit DisplayUnit, waitTimeout time.Duration) error {
+	parsedABI, err := abi.JSON(strings.NewReader(erc20ABI))
+	if err != nil {
+		return fmt.Errorf("解析 ERC-20 ABI 失败: %v", err)
+	}
+	contract := bind.NewBoundContract(token, parsedABI, client, client, client)
+
+	var out []interface{}
+	if err := contract.Call(&bind.CallOpts{From: auth.From}, &out, "allowance", auth.From, spender); err != nil || len(out) != 1 {
+		return fmt.Errorf("查询代币授权额度失败: %v", err)
+	}
+	allowance := out[0].(*big.Int)
+	if allowance.Cmp(required) >= 0 {
+		log.Printf("代币授权额度 %s 不少于本次需要的 %s，无需 approve", formatAllowance(allowance, unit), unit.Format(required))
+		return nil
+	}
+
+	amount := required
+	if infinite {
+		amount = math.MaxBig256
+	}
+	log.Printf("代币授权额度 %s 少于本次需要的 %s，发送 approve 授权 %s 给分账合约 %s",
+		formatAllowance(allowance, unit), unit.Format(required), formatAllowance(amount, unit), spender.Hex())
+
+	if allowance.Sign() > 0 {
+		log.Printf("当前授权额度不为 0，先将授权额度重置为 0")
+		if err := sendApprove(client, contract, auth, spender, new(big.Int), nonces, waitTimeout); err != nil {
+			return err
+		}
+	}
+	return sendApprove(client, contract, auth, spender, amount, nonces, waitTimeout)
+}
+
+// sendApprove 发送 approve 交易并等待确认，nonces 不为 nil 时从中取 nonce
+func sendApprove(client *ethclient.Client, contract *bind.BoundContract, auth *bind.TransactOpts, spender common.Address, amount *big.Int, nonces *lib.NonceManager, waitTimeout time.Duration) error {
+	opts := *auth
+	opts.Value = nil
+	opts.GasLimit = 0
+	if nonces != nil {
+		nonce, err := nonces.Next(context.Background())
+		if err != nil {
+			return fmt.Errorf("获取 approve 交易 nonce 失败: %v", err)
+		}
+		opts.Nonce = new(big.Int).SetUint64(nonce)
+	}
+	tx, err := contract.Transact(&opts, "approve", spender, amount)
+	if err != nil {
+		return fmt.Errorf("发送 approve 交易失败: %v", err)
+	}
+	log.Printf("approve 交易已发送，交易哈希: %s", tx.Hash().Hex())
+	receipt, state, err := waitMinedWithTimeout(client, tx, waitTimeout)
+	if receipt == nil {
+		return fmt.Errorf("等待 approve 交易确认失败 (状态: %s): %v", state, err)
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return fmt.Errorf("approve 交易执行失败: %s", tx.Hash().Hex())
+	}
+	log.Printf("approve 已确认")
+	return nil
+}
+
+// formatAllowance 格式化授权额度，无限授权显示为“无限”
+func formatAllowance(amount *big.Int, unit DisplayUnit) string {
+	if amount.Cmp(math.MaxBig256) == 0 {
+		return "无限"
+	}
+	return unit.Format(amount)
+}
+
+// tokenAmountRequired 计算本次运行所有未跳过批次需要转出的代币总额，批次清单的金额覆盖和跳过设置同样生效
+func tokenAmountRequired(recipients []Recipient, batchSize int, manifest map[int]BatchOverride) *big.Int {
+	total := new(big.Int)
+	for start := 0; start < len(recipients); start += batchSize {
+		end := min(start+batchSize, len(recipients))
+		override, ok := manifest[start/batchSize+1]
+		switch {
+		case ok && override.Skip:
+		case ok && override.Amount != nil:
+			total.Add(total, new(big.Int).Mul(override.Amount, big.NewInt(int64(end-start))))
+		default:
+			total.Add(total, sumRecipientAmounts(recipients[start:end]))
+		}
+	}
+	return total
+}
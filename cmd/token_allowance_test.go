@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestScaleToTokenUnits(t *testing.T) {
+	tests := []struct {
+		amount   string // 按 18 位小数解析后的金额
+		decimals int
+		want     string
+		wantErr  bool
+	}{
+		{"10000000000000000000", 18, "10000000000000000000", false},
+		{"10000000000000000000", 6, "10000000", false},
+		{"1500000000000000000", 0, "", true},
+		{"1000000000000000000", 0, "1", false},
+		{"1", 6, "", true},
+		{"1", 20, "100", false},
+	}
+	for _, tt := range tests {
+		amount, _ := new(big.Int).SetString(tt.amount, 10)
+		got, err := scaleToTokenUnits(amount, tt.decimals)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("scaleToTokenUnits(%s, %d) = %s, want error", tt.amount, tt.decimals, got)
+			}
+			continue
+		}
+		if err != nil || got.String() != tt.want {
+			t.Errorf("scaleToTokenUnits(%s, %d) = %v, %v, want %s", tt.amount, tt.decimals, got, err, tt.want)
+		}
+	}
+}
+
+func TestTokenAmountRequired(t *testing.T) {
+	recipients := make([]Recipient, 5)
+	for i := range recipients {
+		recipients[i] = Recipient{Address: common.BigToAddress(big.NewInt(int64(i + 1))), Amount: big.NewInt(int64(i + 1))}
+	}
+	tests := []struct {
+		name     string
+		manifest map[int]BatchOverride
+		want     int64
+	}{
+		{"no manifest", nil, 15},
+		{"skip batch", map[int]BatchOverride{2: {Batch: 2, Skip: true}}, 8},
+		{"override amount", map[int]BatchOverride{3: {Batch: 3, Amount: big.NewInt(100)}}, 110},
+		{"skip all", map[int]BatchOverride{1: {Skip: true}, 2: {Skip: true}, 3: {Skip: true}}, 0},
+	}
+	for _, tt := range tests {
+		if got := tokenAmountRequired(recipients, 2, tt.manifest); got.Cmp(big.NewInt(tt.want)) != 0 {
+			t.Errorf("%s: got %s, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestLoadBatchTokenABI(t *testing.T) {
+	if _, err := loadBatchTokenABI("", ""); err != nil {
+		t.Fatalf("built-in token ABI: %v", err)
+	}
+	// 原生币的 batchSend 参数缺少代币地址，应被拒绝
+	if _, err := loadBatchTokenABI(batchTransferABI, defaultBatchMethod); err == nil {
+		t.Fatal("batchSend (address[], uint256[]) should be rejected as a token method")
+	}
+}
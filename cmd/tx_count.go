@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+)
+
+var (
+	txCountRPCURL  string
+	txCountAddress string
+	txCountCSVPath string
+	txCountIndex   int
+)
+
+// TxCountCmd 是统计地址已发送交易数量的命令
+var TxCountCmd = &cobra.Command{
+	Use:   "tx-count",
+	Short: "统计地址在链上已发送的交易数量",
+	Long:  `查询地址最新区块的 nonce（即已确认的发出交易数量）以及 pending 中的交易数量，用于核对批量转账后实际上链的交易数。地址可以通过 --address 指定，也可以通过 --csv 和 --index 从钱包 CSV 中选取。`,
+	Run: func(cmd *cobra.Command, args []string) {
+		var address common.Address
+		switch {
+		case txCountAddress != "" && txCountCSVPath != "":
+			log.Fatal("--address 和 --csv 不能同时使用")
+		case txCountAddress != "":
+			if !common.IsHexAddress(txCountAddress) {
+				log.Fatalf("无效的地址 (--address): %s", txCountAddress)
+			}
+			address = common.HexToAddress(txCountAddress)
+		case txCountCSVPath != "":
+			wallets, err := readWalletsFromCSV(txCountCSVPath)
+			if err != nil {
+				log.Fatalf("读取钱包 CSV 文件失败: %v", err)
+			}
+			if txCountIndex < 0 || txCountIndex >= len(wallets) {
+				log.Fatalf("钱包索引超出范围 (0-%d)", len(wallets)-1)
+			}
+			if !common.IsHexAddress(wallets[txCountIndex].Address) {
+				log.Fatalf("CSV 中的地址无效: %s", wallets[txCountIndex].Address)
+			}
+			address = common.HexToAddress(wallets[txCountIndex].Address)
+		default:
+			log.Fatal("请提供地址 (--address) 或钱包 CSV 文件路径 (--csv)")
+		}
+
+		client, err := dialClient(context.Background(), txCountRPCURL)
+		if err != nil {
+			log.Fatalf("连接以太坊网络失败: %v", err)
+		}
+
+		confirmed, err := client.NonceAt(context.Background(), address, nil)
+		if err != nil {
+			log.Fatalf("获取已确认 nonce 失败: %v", err)
+		}
+		pendingNonce, err := client.PendingNonceAt(context.Background(), address)
+		if err != nil {
+			log.Fatalf("获取 pending nonce 失败: %v", err)
+		}
+		var pending uint64
+		if pendingNonce > confirmed {
+			pending = pendingNonce - confirmed
+		}
+
+		fmt.Printf("地址: %s\n", address.Hex())
+		fmt.Printf("已确认发出的交易数: %d\n", confirmed)
+		fmt.Printf("pending 中的交易数: %d\n", pending)
+		fmt.Printf("下一个可用 nonce: %d\n", pendingNonce)
+	},
+}
+
+func init() {
+	TxCountCmd.Flags().StringVar(&txCountRPCURL, "rpc", "https://bsc-dataseed.binance.org/", "以太坊 RPC URL")
+	TxCountCmd.Flags().StringVar(&txCountAddress, "address", "", "要查询的地址")
+	TxCountCmd.Flags().StringVar(&txCountCSVPath, "csv", "", "钱包 CSV 文件路径（与 --index 一起使用）")
+	TxCountCmd.Flags().IntVar(&txCountIndex, "index", 0, "使用 CSV 中第几个钱包的地址（从 0 开始）")
+}
@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// 交易的最终状态，记录在报告的 state 列中
+const (
+	TxStateMined    = "mined"    // 已上链
+	TxStatePending  = "pending"  // 仍在交易池中等待打包
+	TxStateReplaced = "replaced" // 不在交易池中，且同一 nonce 已被其他交易使用
+	TxStateDropped  = "dropped"  // 不在交易池中，也从未上链
+	TxStateUnknown  = "unknown"  // 已打包，但多次查询仍拿不到回执，无法确认执行结果
+)
+
+// receiptRetryInterval 是交易已打包但回执尚未可查时重新查询回执的间隔
+const receiptRetryInterval = time.Second
+
+// waitMinedWithTimeout 等待交易确认，timeout 为 0 时一直等待；
+// 超时后查询交易池和链上状态，返回交易的分类
+func waitMinedWithTimeout(client *ethclient.Client, tx *types.Transaction, timeout time.Duration) (*types.Receipt, string, error) {
+	return waitMinedContext(context.Background(), client, tx, timeout)
+}
+
+// waitMinedContext 与 waitMinedWithTimeout 相同，但在 ctx 结束时也停止等待
+func waitMinedContext(ctx context.Context, client *ethclient.Client, tx *types.Transaction, timeout time.Duration) (*types.Receipt, string, error) {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	receipt, err := bind.WaitMined(ctx, client, tx)
+	if err == nil {
+		return receipt, TxStateMined, nil
+	}
+	return classifyTransaction(client, tx, err)
+}
+
+// classifyTransaction 判断等待超时的交易是已上链、仍在等待、被替换还是被丢弃。
+// 只有拿到回执时才返回 TxStateMined，已打包但回执始终不可查时返回 TxStateUnknown
+func classifyTransaction(client *ethclient.Client, tx *types.Transaction, waitErr error) (*types.Receipt, string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	receipt, err := client.TransactionReceipt(ctx, tx.Hash())
+	if err == nil {
+		return receipt, TxStateMined, nil
+	}
+	_, isPending, err := client.TransactionByHash(ctx, tx.Hash())
+	if err == nil {
+		if isPending {
+			return nil, TxStatePending, waitErr
+		}
+		// 已打包但节点的回执索引可能落后，在查询时限内重新获取回执
+		for {
+			select {
+			case <-ctx.Done():
+				return nil, TxStateUnknown, waitErr
+			case <-time.After(receiptRetryInterval):
+			}
+			if receipt, err := client.TransactionReceipt(ctx, tx.Hash()); err == nil {
+				return receipt, TxStateMined, nil
+			}
+		}
+	}
+	if !errors.Is(err, ethereum.NotFound) {
+		return nil, "", waitErr
+	}
+
+	// 交易池和链上都找不到：如果发送者的 nonce 已越过该交易，说明被替换
+	sender, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+	if err == nil {
+		if nonce, err := client.NonceAt(ctx, sender, nil); err == nil && nonce > tx.Nonce() {
+			return nil, TxStateReplaced, waitErr
+		}
+	}
+	return nil, TxStateDropped, waitErr
+}
@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"math/big"
+	"strings"
+)
+
+// resolveUSDAmount 按价格接口返回的原生币美元价格，把 amountUSD 美元换算为原生币金额（Wei，向下取整）。
+// 价格获取失败时使用 fallbackPrice，fallbackPrice 为 0 时返回错误
+func resolveUSDAmount(amountUSD, priceURL, pricePath string, fallbackPrice float64) (*big.Int, error) {
+	usd, ok := parseDecimal(strings.ReplaceAll(amountUSD, ",", ""))
+	if !ok || usd.Sign() <= 0 {
+		return nil, fmt.Errorf("美元金额无效 (--amount-usd): %s", amountUSD)
+	}
+	if priceURL == "" {
+		return nil, fmt.Errorf("使用 --amount-usd 时必须提供价格接口 (--price-url)")
+	}
+	if fallbackPrice < 0 {
+		return nil, fmt.Errorf("备用价格不能为负数 (--price-fallback)")
+	}
+
+	var price *big.Rat
+	value, err := fetchJSONNumber(priceURL, pricePath)
+	if err == nil {
+		var ok bool
+		if price, ok = parseDecimal(value); !ok || price.Sign() <= 0 {
+			err = fmt.Errorf("价格 %s 无效", value)
+		}
+	}
+	if err != nil {
+		if fallbackPrice == 0 {
+			return nil, fmt.Errorf("获取原生币美元价格失败，已中止（可通过 --price-fallback 指定备用价格）: %v", err)
+		}
+		log.Printf("获取原生币美元价格失败，使用备用价格 %g USD: %v", fallbackPrice, err)
+		price = new(big.Rat).SetFloat64(fallbackPrice)
+	}
+
+	native := new(big.Rat).Quo(usd, price)
+	native.Mul(native, new(big.Rat).SetInt(big.NewInt(1e18)))
+	amountWei := new(big.Int).Quo(native.Num(), native.Denom())
+	if amountWei.Sign() <= 0 {
+		return nil, fmt.Errorf("换算后的转账金额为 0 (%s USD)", amountUSD)
+	}
+	log.Printf("美元金额: %s USD，原生币价格: %s USD，换算后每个钱包转账 %s", usd.FloatString(2), price.FloatString(4), formatEther(amountWei))
+	return amountWei, nil
+}
+
+// parseDecimal 解析十进制数，与 parseAmount 一样用 decimalAmountPattern 排除 big.Rat 也能解析的分数（1/3）和十六进制（0x10）等写法
+func parseDecimal(value string) (*big.Rat, bool) {
+	normalized := strings.ToLower(strings.TrimSpace(value))
+	if !decimalAmountPattern.MatchString(normalized) {
+		return nil, false
+	}
+	return new(big.Rat).SetString(normalized)
+}
@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"AccountSplitting/lib"
+	"bufio"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/tyler-smith/go-bip39"
+)
+
+var (
+	validateMnemonic     string
+	validateMnemonicFile string
+)
+
+// mnemonicCheck 是单个助记词的校验结果
+type mnemonicCheck struct {
+	Label     string
+	WordCount int
+	Valid     bool
+	Reason    string
+}
+
+// checkMnemonic 校验助记词是否为合法的 BIP-39 助记词，无效时区分单词数量、未知单词和校验和错误
+func checkMnemonic(mnemonic string) mnemonicCheck {
+	words := strings.Fields(mnemonic)
+	check := mnemonicCheck{WordCount: len(words)}
+	if bip39.IsMnemonicValid(strings.Join(words, " ")) {
+		check.Valid = true
+		return check
+	}
+
+	validLength := false
+	for _, count := range lib.MnemonicWordCounts {
+		if count == len(words) {
+			validLength = true
+			break
+		}
+	}
+	if !validLength {
+		check.Reason = fmt.Sprintf("单词数量错误（%d 个，应为 12/15/18/21/24 个）", len(words))
+		return check
+	}
+	for i, word := range words {
+		if _, ok := bip39.GetWordIndex(word); !ok {
+			check.Reason = fmt.Sprintf("第 %d 个单词 %q 不在 BIP-39 词表中", i+1, word)
+			return check
+		}
+	}
+	check.Reason = "校验和错误（可能有单词抄错或顺序颠倒）"
+	return check
+}
+
+// readMnemonicLines 读取助记词文件，每行一个助记词，空行和 # 开头的行被忽略
+func readMnemonicLines(path string) ([]mnemonicCheck, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("打开助记词文件失败: %v", err)
+	}
+	defer file.Close()
+
+	var checks []mnemonicCheck
+	scanner := bufio.NewScanner(file)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		check := checkMnemonic(line)
+		check.Label = fmt.Sprintf("第 %d 行", lineNum)
+		checks = append(checks, check)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("读取助记词文件失败: %v", err)
+	}
+	return checks, nil
+}
+
+// ValidateMnemonicCmd 是校验助记词是否为合法 BIP-39 助记词的命令
+var ValidateMnemonicCmd = &cobra.Command{
+	Use:   "validate-mnemonic",
+	Short: "校验助记词的单词数量和 BIP-39 校验和",
+	Long:  `在导入或派生之前校验助记词是否为合法的 BIP-39 助记词，输出单词数量；无效时指出是单词数量、未知单词还是校验和错误，用于发现抄写错误。存在无效助记词时以非零状态退出。`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if (validateMnemonic == "") == (validateMnemonicFile == "") {
+			log.Fatal("请提供 --mnemonic 或 --file 其中之一")
+		}
+
+		var checks []mnemonicCheck
+		if validateMnemonic != "" {
+			check := checkMnemonic(validateMnemonic)
+			check.Label = "助记词"
+			checks = append(checks, check)
+		} else {
+			var err error
+			checks, err = readMnemonicLines(validateMnemonicFile)
+			if err != nil {
+				log.Fatal(err)
+			}
+			if len(checks) == 0 {
+				log.Fatalf("助记词文件中没有助记词: %s", validateMnemonicFile)
+			}
+		}
+
+		invalid := 0
+		for _, check := range checks {
+			if check.Valid {
+				log.Printf("%s: 有效（%d 个单词）", check.Label, check.WordCount)
+				continue
+			}
+			invalid++
+			log.Printf("%s: 无效（%d 个单词），%s", check.Label, check.WordCount, check.Reason)
+		}
+		log.Printf("校验完成: 有效 %d 个，无效 %d 个", len(checks)-invalid, invalid)
+		if invalid > 0 {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	ValidateMnemonicCmd.Flags().StringVar(&validateMnemonic, "mnemonic", "", "要校验的助记词（用引号括起来）")
+	ValidateMnemonicCmd.Flags().StringVar(&validateMnemonicFile, "file", "", "助记词文件路径，每行一个助记词")
+}
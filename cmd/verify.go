@@ -11,7 +11,11 @@ import (
 	"github.com/spf13/cobra"
 )
 
-var verifyFile string
+var (
+	verifyFile     string
+	verifyFailFast bool
+	verifyNoHeader bool // CSV 没有表头，第一行即为数据
+)
 
 var verifyCmd = &cobra.Command{
 	Use:   "verifycsv",
@@ -27,6 +31,8 @@ var verifyCmd = &cobra.Command{
 
 func init() {
 	verifyCmd.Flags().StringVarP(&verifyFile, "file", "f", "", "要校验的CSV文件路径")
+	verifyCmd.Flags().BoolVar(&verifyNoHeader, "no-header", false, "CSV 没有表头，从第 1 行开始校验（未指定时若第 1 行第二列是有效私钥也会自动按无表头处理）")
+	verifyCmd.Flags().BoolVar(&verifyFailFast, "fail-fast", false, "遇到第一个不匹配的地址立即停止并以非零状态退出")
 	rootCmd.AddCommand(verifyCmd)
 }
 
@@ -39,23 +45,36 @@ func verifyCSV(filePath string) {
 	}
 	defer file.Close()
 	reader := csv.NewReader(file)
-	header, err := reader.Read()
+	first, err := reader.Read()
 	if err != nil {
 		fmt.Println("读取CSV标题失败:", err)
 		return
 	}
-	fmt.Printf("CSV 标题: %v\n", header)
-	if len(header) < 2 {
+	if len(first) < 2 {
 		fmt.Println("错误: CSV 文件格式不正确，至少需要地址和私钥两列")
 		return
 	}
+	// 第一行第二列是有效私钥时说明文件没有表头
+	noHeader := verifyNoHeader || looksLikePrivateKey(first[1])
+	rowNumber := 2
+	if noHeader {
+		if !verifyNoHeader {
+			fmt.Println("第 1 行第二列是有效私钥，按无表头文件处理")
+		}
+		rowNumber = 1
+	} else {
+		fmt.Printf("CSV 标题: %v\n", first)
+	}
 	total := 0
 	matched := 0
 	mismatched := make([][2]string, 0)
-	for rowNumber := 2; ; rowNumber++ {
-		row, err := reader.Read()
-		if err != nil {
-			break
+	for ; ; rowNumber++ {
+		row := first
+		if rowNumber > 1 {
+			row, err = reader.Read()
+			if err != nil {
+				break
+			}
 		}
 		if len(row) < 2 {
 			fmt.Printf("行 %d: 格式错误 - 列数不足\n", rowNumber)
@@ -66,6 +85,10 @@ func verifyCSV(filePath string) {
 		if address == "" {
 			fmt.Printf("行 %d: ❌ 地址为空\n", rowNumber)
 			mismatched = append(mismatched, [2]string{fmt.Sprint(rowNumber), "地址为空"})
+			if verifyFailFast {
+				fmt.Println("\n已启用 --fail-fast，验证中止")
+				os.Exit(1)
+			}
 			continue
 		}
 		result, msg := checkAddressPrivateKey(address, privateKey)
@@ -76,6 +99,10 @@ func verifyCSV(filePath string) {
 		} else {
 			mismatched = append(mismatched, [2]string{fmt.Sprint(rowNumber), address + " (" + msg + ")"})
 			fmt.Printf("行 %d: ❌ 匹配失败 - %s (%s)\n", rowNumber, address, msg)
+			if verifyFailFast {
+				fmt.Println("\n已启用 --fail-fast，验证中止")
+				os.Exit(1)
+			}
 		}
 	}
 	fmt.Println("\n验证结果总结:")
@@ -90,6 +117,16 @@ func verifyCSV(filePath string) {
 	}
 }
 
+// looksLikePrivateKey 判断 s 是否为有效的十六进制私钥（可带 0x 前缀）
+func looksLikePrivateKey(s string) bool {
+	cleanKey := strings.TrimPrefix(strings.TrimSpace(s), "0x")
+	if len(cleanKey) != 64 {
+		return false
+	}
+	_, err := crypto.HexToECDSA(cleanKey)
+	return err == nil
+}
+
 func checkAddressPrivateKey(address, privateKey string) (bool, string) {
 	if privateKey == "" {
 		return false, "私钥为空"
@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// balanceShortfall 是余额增加量少于已发送金额的接收者
+type balanceShortfall struct {
+	Address  common.Address
+	Expected *big.Int
+	Actual   *big.Int
+}
+
+// balanceCheck 记录接收者在发送前的余额和确认成功的转账金额，发送完成后核对余额增加量
+type balanceCheck struct {
+	client   *ethclient.Client
+	order    []common.Address
+	before   map[common.Address]*big.Int
+	expected map[common.Address]*big.Int
+}
+
+// newBalanceCheck 查询接收者发送前的余额，重复地址只查询一次
+func newBalanceCheck(client *ethclient.Client, addresses []common.Address) (*balanceCheck, error) {
+	check := &balanceCheck{
+		client:   client,
+		before:   make(map[common.Address]*big.Int),
+		expected: make(map[common.Address]*big.Int),
+	}
+	for _, addr := range addresses {
+		if _, ok := check.expected[addr]; ok {
+			continue
+		}
+		check.expected[addr] = new(big.Int)
+		check.order = append(check.order, addr)
+	}
+	balances, err := fetchBalancesIndividually(client, check.order, 10)
+	if err != nil {
+		return nil, fmt.Errorf("查询接收者发送前余额失败: %v", err)
+	}
+	for i, addr := range check.order {
+		check.before[addr] = balances[i]
+	}
+	return check, nil
+}
+
+// add 记录一笔已确认成功转入 addr 的金额
+func (c *balanceCheck) add(addr common.Address, amount *big.Int) {
+	if expected, ok := c.expected[addr]; ok {
+		expected.Add(expected, amount)
+	}
+}
+
+// verify 重新查询收到转账的接收者余额，返回余额增加量少于已发送金额的接收者和核对的接收者数量
+func (c *balanceCheck) verify() ([]balanceShortfall, int, error) {
+	var addresses []common.Address
+	for _, addr := range c.order {
+		if c.expected[addr].Sign() > 0 {
+			addresses = append(addresses, addr)
+		}
+	}
+	balances, err := fetchBalancesIndividually(c.client, addresses, 10)
+	if err != nil {
+		return nil, 0, fmt.Errorf("查询接收者发送后余额失败: %v", err)
+	}
+	var shortfalls []balanceShortfall
+	for i, addr := range addresses {
+		delta := new(big.Int).Sub(balances[i], c.before[addr])
+		if delta.Cmp(c.expected[addr]) < 0 {
+			shortfalls = append(shortfalls, balanceShortfall{Address: addr, Expected: c.expected[addr], Actual: delta})
+		}
+	}
+	return shortfalls, len(addresses), nil
+}
+
+// logBalanceVerification 核对余额增加量并输出结果，查询失败时只记录日志
+func logBalanceVerification(check *balanceCheck, unit DisplayUnit) {
+	log.Printf("正在核对接收者余额变化...")
+	shortfalls, checked, err := check.verify()
+	if err != nil {
+		log.Printf("余额核对失败: %v", err)
+		return
+	}
+	if len(shortfalls) == 0 {
+		log.Printf("余额核对通过，%d 个接收者的余额增加量均不少于已发送金额", checked)
+		return
+	}
+	log.Printf("余额核对发现 %d/%d 个接收者未收到预期金额:", len(shortfalls), checked)
+	for _, s := range shortfalls {
+		log.Printf("- %s: 已发送 %s，余额实际增加 %s", s.Address.Hex(), unit.Format(s.Expected), unit.Format(s.Actual))
+	}
+}
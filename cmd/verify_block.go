@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// blockHashResult 是单个节点返回的区块哈希
+type blockHashResult struct {
+	URL   string
+	Hash  common.Hash
+	Error error
+}
+
+// fetchBlockHashes 并发向每个节点查询指定高度的区块哈希
+func fetchBlockHashes(nodes []string, blockNumber uint64, timeout time.Duration) []blockHashResult {
+	results := make([]blockHashResult, len(nodes))
+	var wg sync.WaitGroup
+	for i, node := range nodes {
+		wg.Add(1)
+		go func(i int, nodeURL string) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+
+			results[i] = blockHashResult{URL: nodeURL}
+			client, err := dialClient(ctx, nodeURL)
+			if err != nil {
+				results[i].Error = err
+				return
+			}
+			defer client.Close()
+			header, err := client.HeaderByNumber(ctx, new(big.Int).SetUint64(blockNumber))
+			if err != nil {
+				results[i].Error = err
+				return
+			}
+			results[i].Hash = header.Hash()
+		}(i, node)
+	}
+	wg.Wait()
+	return results
+}
+
+// majorityHash 返回多数节点认可的区块哈希及认可的节点数量
+func majorityHash(results []blockHashResult) (common.Hash, int) {
+	counts := make(map[common.Hash]int)
+	var consensus common.Hash
+	for _, result := range results {
+		if result.Error != nil {
+			continue
+		}
+		counts[result.Hash]++
+		if counts[result.Hash] > counts[consensus] {
+			consensus = result.Hash
+		}
+	}
+	return consensus, counts[consensus]
+}
+
+// reportBlockVerification 比较各节点在指定高度的区块哈希，输出多数哈希和不一致的节点，
+// 返回哈希与多数不一致的节点 URL
+func reportBlockVerification(nodes []string, blockNumber uint64, timeout time.Duration) ([]string, error) {
+	results := fetchBlockHashes(nodes, blockNumber, timeout)
+	consensus, agreed := majorityHash(results)
+	if agreed == 0 {
+		return nil, fmt.Errorf("没有节点成功返回区块 %d", blockNumber)
+	}
+
+	log.Printf("区块 %d 校验结果: 多数哈希 %s (%d/%d 个节点一致)", blockNumber, consensus.Hex(), agreed, len(nodes))
+	var dissenters []string
+	for _, result := range results {
+		switch {
+		case result.Error != nil:
+			log.Printf("- %s: 查询失败: %v", result.URL, result.Error)
+		case result.Hash != consensus:
+			log.Printf("- %s: 哈希不一致 %s", result.URL, result.Hash.Hex())
+			dissenters = append(dissenters, result.URL)
+		}
+	}
+	if len(dissenters) == 0 {
+		log.Printf("所有成功响应的节点区块哈希一致")
+	}
+	return dissenters, nil
+}
@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// recipientArgNames 是单笔转账事件中表示接收者的常见参数名
+var recipientArgNames = []string{"to", "recipient", "receiver", "account", "dst"}
+
+// decodeTransferLogs 按 ABI 解码回执中合约发出的事件，提取每个接收者收到的金额。
+// 支持两类事件：带 (address[], uint256[]) 参数的批量事件，以及每个接收者一条的 (address, uint256) 事件。
+func decodeTransferLogs(parsedABI abi.ABI, contract common.Address, logs []*types.Log) (map[common.Address]*big.Int, int, error) {
+	received := make(map[common.Address]*big.Int)
+	transfers := 0
+	add := func(addr common.Address, amount *big.Int) {
+		if received[addr] == nil {
+			received[addr] = new(big.Int)
+		}
+		received[addr].Add(received[addr], amount)
+		transfers++
+	}
+
+	for _, entry := range logs {
+		if entry.Address != contract || len(entry.Topics) == 0 {
+			continue
+		}
+		event, err := parsedABI.EventByID(entry.Topics[0])
+		if err != nil {
+			continue
+		}
+
+		values := make(map[string]interface{})
+		if len(entry.Data) > 0 {
+			if err := parsedABI.UnpackIntoMap(values, event.Name, entry.Data); err != nil {
+				return nil, 0, fmt.Errorf("解码事件 %s 失败: %v", event.Name, err)
+			}
+		}
+		var indexed abi.Arguments
+		for _, input := range event.Inputs {
+			if input.Indexed {
+				indexed = append(indexed, input)
+			}
+		}
+		if err := abi.ParseTopicsIntoMap(values, indexed, entry.Topics[1:]); err != nil {
+			return nil, 0, fmt.Errorf("解码事件 %s 的 topics 失败: %v", event.Name, err)
+		}
+
+		// 批量事件：接收者数组和金额数组
+		var addrs []common.Address
+		var amounts []*big.Int
+		for _, input := range event.Inputs {
+			switch v := values[input.Name].(type) {
+			case []common.Address:
+				addrs = v
+			case []*big.Int:
+				amounts = v
+			}
+		}
+		if addrs != nil && amounts != nil {
+			if len(addrs) != len(amounts) {
+				return nil, 0, fmt.Errorf("事件 %s 的接收者数量 (%d) 与金额数量 (%d) 不一致", event.Name, len(addrs), len(amounts))
+			}
+			for i := range addrs {
+				add(addrs[i], amounts[i])
+			}
+			continue
+		}
+
+		// 单笔事件：优先使用常见的接收者参数名，否则使用最后一个地址参数
+		var recipient *common.Address
+		var amount *big.Int
+		for _, input := range event.Inputs {
+			switch v := values[input.Name].(type) {
+			case common.Address:
+				addr := v
+				if recipient == nil || isRecipientArg(input.Name) {
+					recipient = &addr
+				}
+			case *big.Int:
+				if amount == nil {
+					amount = v
+				}
+			}
+		}
+		if recipient != nil && amount != nil {
+			add(*recipient, amount)
+		}
+	}
+	return received, transfers, nil
+}
+
+func isRecipientArg(name string) bool {
+	for _, candidate := range recipientArgNames {
+		if strings.EqualFold(name, candidate) {
+			return true
+		}
+	}
+	return false
+}
+
+// verifyBatchLogs 比较事件中的转账与请求的接收者和金额，返回发现的差异
+func verifyBatchLogs(parsedABI abi.ABI, contract common.Address, receipt *types.Receipt, recipients []common.Address, amounts []*big.Int) []string {
+	received, transfers, err := decodeTransferLogs(parsedABI, contract, receipt.Logs)
+	if err != nil {
+		return []string{err.Error()}
+	}
+	if transfers == 0 {
+		return []string{"回执中没有可解码的转账事件"}
+	}
+
+	expected := make(map[common.Address]*big.Int)
+	for i, recipient := range recipients {
+		if expected[recipient] == nil {
+			expected[recipient] = new(big.Int)
+		}
+		expected[recipient].Add(expected[recipient], amounts[i])
+	}
+
+	var issues []string
+	if transfers != len(recipients) {
+		issues = append(issues, fmt.Sprintf("事件中的转账数量 %d 与请求的接收者数量 %d 不一致", transfers, len(recipients)))
+	}
+	for _, addr := range recipients {
+		want := expected[addr]
+		if want == nil {
+			continue
+		}
+		delete(expected, addr)
+		got := received[addr]
+		if got == nil {
+			issues = append(issues, fmt.Sprintf("%s 没有对应的转账事件", addr.Hex()))
+		} else if got.Cmp(want) != 0 {
+			issues = append(issues, fmt.Sprintf("%s 事件金额 %s 与请求金额 %s 不一致", addr.Hex(), got.String(), want.String()))
+		}
+	}
+	requested := make(map[common.Address]bool)
+	for _, addr := range recipients {
+		requested[addr] = true
+	}
+	for addr := range received {
+		if !requested[addr] {
+			issues = append(issues, fmt.Sprintf("事件中出现了未请求的接收者 %s", addr.Hex()))
+		}
+	}
+	return issues
+}
@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// batchWebhookPayload 是每批交易确认后发送到 --webhook-url 的 JSON 内容
+type batchWebhookPayload struct {
+	RunID        string   `json:"run_id"`
+	Batch        int      `json:"batch"`
+	TotalBatches int      `json:"total_batches"`
+	TxHash       string   `json:"tx_hash"`
+	GasUsed      uint64   `json:"gas_used"`
+	Recipients   []string `json:"recipients"`
+	Amounts      []string `json:"amounts"`
+	TotalValue   string   `json:"total_value"`
+	Success      bool     `json:"success"`
+	Error        string   `json:"error,omitempty"`
+}
+
+// newBatchWebhookPayload 根据批次的接收者和金额（以 Wei 为单位）构造回调内容
+func newBatchWebhookPayload(batch, totalBatches int, txHash string, gasUsed uint64, recipients []common.Address, amounts []*big.Int, errMsg string) batchWebhookPayload {
+	payload := batchWebhookPayload{
+		RunID:        RunID,
+		Batch:        batch,
+		TotalBatches: totalBatches,
+		TxHash:       txHash,
+		GasUsed:      gasUsed,
+		Success:      errMsg == "",
+		Error:        errMsg,
+	}
+	total := new(big.Int)
+	for i, recipient := range recipients {
+		payload.Recipients = append(payload.Recipients, recipient.Hex())
+		payload.Amounts = append(payload.Amounts, amounts[i].String())
+		total.Add(total, amounts[i])
+	}
+	payload.TotalValue = total.String()
+	return payload
+}
+
+// postWebhook 以 JSON 格式 POST payload 到 url，非 2xx 状态码视为失败
+func postWebhook(url string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("序列化回调内容失败: %v", err)
+	}
+	httpClient := &http.Client{Timeout: 10 * time.Second}
+	resp, err := httpClient.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("请求回调地址失败: %v", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<20))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("回调地址返回状态码 %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -38,6 +38,15 @@ contract BatchTransfer {
             require(success, "Transfer failed");
         }
     }
+    // 批量发送 ERC-20 代币，调用者需先 approve 本合约不少于总金额的额度
+    function batchSendToken(address token, address[] calldata recipients, uint256[] calldata amounts) external {
+        require(recipients.length == amounts.length, "Length mismatch");
+        for (uint i = 0; i < recipients.length; i++) {
+            // 使用低级调用并允许空返回值，兼容 USDT 等 transferFrom 不返回 bool 的代币
+            (bool success, bytes memory data) = token.call(abi.encodeWithSignature("transferFrom(address,address,uint256)", msg.sender, recipients[i], amounts[i]));
+            require(success && (data.length == 0 ? token.code.length > 0 : abi.decode(data, (bool))), "Token transfer failed");
+        }
+    }
     // Owner将合约中的全部余额转出到指定地址
     function withdraw(address payable to) external onlyOwner {
         uint256 balance = address(this).balance;
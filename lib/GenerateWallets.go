@@ -7,8 +7,10 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"io"
 	"log"
 	"os"
+	"strings"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
@@ -16,13 +18,60 @@ import (
 	"github.com/tyler-smith/go-bip39"
 )
 
-func GWallets(numberOfWallets int) (records [][]string, err error) {
-	// 生成指定数量的钱包地址和私钥，并将它们写入文件
-	for i := 0; i < numberOfWallets; i++ {
+// GenOptions 控制钱包生成过程中的输出
+type GenOptions struct {
+	Progress bool // 显示单行刷新的进度条
+	Verbose  bool // 详细模式下每 LogEvery 个钱包输出一条日志
+	LogEvery int
+	Format   string // 输出格式 (csv, json)，为空时使用 csv
+	Strength int    // 助记词熵的位数 (128/160/192/224/256)，为 0 时使用 128
+	Append   bool   // 追加到已有的 CSV 文件，而不是覆盖
+}
+
+// MnemonicWordCounts 是支持的助记词熵位数与单词数量的对应关系
+var MnemonicWordCounts = map[int]int{128: 12, 160: 15, 192: 18, 224: 21, 256: 24}
+
+// genReporter 根据 GenOptions 汇报生成进度
+type genReporter struct {
+	opts     GenOptions
+	progress *Progress
+}
+
+func newGenReporter(total int, opts GenOptions) *genReporter {
+	r := &genReporter{opts: opts}
+	if opts.Progress {
+		r.progress = NewProgress(total)
+	}
+	return r
+}
+
+// generated 在第 n 个钱包生成后调用
+func (r *genReporter) generated(n int, address string) {
+	if r.progress != nil {
+		r.progress.Add(1)
+	}
+	if r.opts.Verbose && r.opts.LogEvery > 0 && n%r.opts.LogEvery == 0 {
+		if r.progress != nil {
+			// 换行后再输出日志，避免与进度条混在同一行
+			fmt.Fprintln(os.Stderr)
+		}
+		log.Printf("Generated wallet %d: %s\n", n, address)
+	}
+}
+
+func (r *genReporter) finish() {
+	if r.progress != nil {
+		r.progress.Finish()
+	}
+}
+
+// generateWallet 生成一个钱包，返回 [私钥, 地址]
+func generateWallet() ([]string, error) {
+	for {
 		// 生成一个新的私钥
 		privateKey, err := ecdsa.GenerateKey(crypto.S256(), rand.Reader)
 		if err != nil {
-			log.Fatal(err)
+			return nil, err
 		}
 		// 将私钥转换为字节序列
 		privateKeyBytes := privateKey.D.Bytes()
@@ -31,79 +80,173 @@ func GWallets(numberOfWallets int) (records [][]string, err error) {
 		// 转换一次作为privateKey是否有效的检查
 		_, err = crypto.HexToECDSA(privateKeyHex)
 		if err != nil {
-			i--
 			continue
 		}
 		// 根据私钥生成公钥和地址
 		publicKey := privateKey.Public()
 		publicKeyECDSA, ok := publicKey.(*ecdsa.PublicKey)
 		if !ok {
-			return records, errors.New("生成公钥失败！")
+			return nil, errors.New("生成公钥失败！")
 		}
 		address := crypto.PubkeyToAddress(*publicKeyECDSA).Hex()
-		// 将私钥和地址写入文件
-		records = append(records, []string{privateKeyHex, address})
+		return []string{privateKeyHex, address}, nil
+	}
+}
+
+func GWallets(numberOfWallets int) (records [][]string, err error) {
+	// 生成指定数量的钱包地址和私钥
+	for i := 0; i < numberOfWallets; i++ {
+		record, err := generateWallet()
+		if err != nil {
+			return records, err
+		}
+		records = append(records, record)
 	}
 	return records, nil
 }
-func GWalletsAndWirte(numberOfWallets int, fileName string) error {
-	// 创建名为 secret.csv 的文件，并写入表头
-	file, err := os.Create(fileName)
+func GWalletsAndWirte(numberOfWallets int, fileName string, opts GenOptions) error {
+	// 创建名为 secret.csv 的文件，追加模式下校验已有文件的格式
+	file, _, err := openWalletFile(fileName, opts, func(first []string) error {
+		if len(first) != 2 || !common.IsHexAddress(first[1]) {
+			return fmt.Errorf("已有文件的格式不是 [私钥, 地址]: %v", first)
+		}
+		return nil
+	})
 	if err != nil {
-		log.Fatal(err)
 		return err
 	}
 	defer file.Close()
-	writer := csv.NewWriter(file)
-	defer writer.Flush()
-	records, err := GWallets(numberOfWallets)
+	// CSV 不写表头，每行为 [私钥, 地址]
+	writer, err := newWalletWriter(file, opts.Format, nil, func(w Wallet) []string {
+		return []string{w.PrivateKey, w.Address}
+	})
 	if err != nil {
-		log.Fatal(err)
 		return err
 	}
-	for _, record := range records {
-		if err := writer.Write(record); err != nil {
-			log.Fatal(err)
-			return err
+	reporter := newGenReporter(numberOfWallets, opts)
+	for i := 0; i < numberOfWallets; i++ {
+		record, err := generateWallet()
+		if err != nil {
+			return fmt.Errorf("生成第 %d 个钱包失败（已成功写入 %d 个）: %v", i+1, writer.Written(), err)
+		}
+		if err := writer.Write(Wallet{Address: record[1], PrivateKey: record[0]}); err != nil {
+			return fmt.Errorf("写入第 %d 个钱包失败（已成功写入 %d 个）: %v", i+1, writer.Written(), err)
 		}
+		reporter.generated(i+1, record[1])
 	}
+	if err := finishWalletFile(writer, file); err != nil {
+		return err
+	}
+	reporter.finish()
 	log.Printf("%d 个钱包地址和私钥已生成并写入文件！", numberOfWallets)
 	return nil
 }
-func GmwsAndWirte(numWallets int, csvFile string) error {
-	file, err := os.Create(csvFile)
+func GmwsAndWirte(numWallets int, csvFile string, opts GenOptions) error {
+	header := []string{"Address", "Private Key", "Mnemonic"}
+	file, isNew, err := openWalletFile(csvFile, opts, func(first []string) error {
+		if strings.Join(first, ",") != strings.Join(header, ",") {
+			return fmt.Errorf("已有文件的表头 %v 与期望的 %v 不一致", first, header)
+		}
+		return nil
+	})
 	if err != nil {
-		log.Fatalf("Failed to create file: %v", err)
 		return err
 	}
 	defer file.Close()
-	writer := csv.NewWriter(file)
-	defer writer.Flush()
-	// 写入CSV文件头
-	err = writer.Write([]string{"Address", "Private Key", "Mnemonic"})
+	// 写入CSV文件头（追加到已有文件时不重复写入）
+	if !isNew {
+		header = nil
+	}
+	writer, err := newWalletWriter(file, opts.Format, header, func(w Wallet) []string {
+		return []string{w.Address, w.PrivateKey, w.Mnemonic}
+	})
 	if err != nil {
-		log.Fatalf("Failed to write header to CSV file: %v", err)
-		return err
+		return fmt.Errorf("写入文件头失败: %v", err)
 	}
+	reporter := newGenReporter(numWallets, opts)
 	for i := 0; i < numWallets; i++ {
-		address, privateKey, mnemonic, err := GMnemonicW()
+		address, privateKey, mnemonic, err := GMnemonicWStrength(opts.Strength)
 		if err != nil {
-			log.Fatalf("Failed to generate wallet: %v", err)
-			return err
+			return fmt.Errorf("生成第 %d 个钱包失败（已成功写入 %d 个）: %v", i+1, writer.Written(), err)
 		}
-		err = writer.Write([]string{address.Hex(), privateKey, mnemonic})
+		err = writer.Write(Wallet{Address: address.Hex(), PrivateKey: privateKey, Mnemonic: mnemonic})
 		if err != nil {
-			log.Fatalf("Failed to write wallet to CSV file: %v", err)
-			return err
+			return fmt.Errorf("写入第 %d 个钱包失败（已成功写入 %d 个）: %v", i+1, writer.Written(), err)
 		}
-		log.Printf("Generated wallet %d: %s\n", i+1, address.Hex())
+		reporter.generated(i+1, address.Hex())
 	}
+	if err := finishWalletFile(writer, file); err != nil {
+		return err
+	}
+	reporter.finish()
 	log.Println("All wallets generated and saved to CSV file successfully.")
 	return nil
 }
+
+// openWalletFile 创建输出文件；追加模式下打开已有文件，用 validate 校验其第一行，
+// 返回的 bool 表示文件是否为新文件（或空文件），需要写入表头
+func openWalletFile(path string, opts GenOptions, validate func(first []string) error) (*os.File, bool, error) {
+	if !opts.Append {
+		file, err := os.Create(path)
+		if err != nil {
+			return nil, false, fmt.Errorf("创建文件失败: %v", err)
+		}
+		return file, true, nil
+	}
+	if opts.Format == "json" {
+		return nil, false, errors.New("追加模式仅支持 csv 格式")
+	}
+
+	isNew := true
+	if existing, err := os.Open(path); err == nil {
+		first, readErr := csv.NewReader(existing).Read()
+		existing.Close()
+		switch {
+		case readErr == io.EOF:
+			// 空文件视为新文件
+		case readErr != nil:
+			return nil, false, fmt.Errorf("读取已有文件失败: %v", readErr)
+		default:
+			if err := validate(first); err != nil {
+				return nil, false, err
+			}
+			isNew = false
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, false, fmt.Errorf("打开已有文件失败: %v", err)
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, false, fmt.Errorf("打开文件失败: %v", err)
+	}
+	return file, isNew, nil
+}
+
+// finishWalletFile 刷新剩余缓冲并同步到磁盘，磁盘写满等错误在这里才会暴露
+func finishWalletFile(writer walletWriter, file *os.File) error {
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("写入文件失败（已成功写入 %d 个钱包）: %v", writer.Written(), err)
+	}
+	if err := file.Sync(); err != nil {
+		return fmt.Errorf("同步文件失败（已成功写入 %d 个钱包）: %v", writer.Written(), err)
+	}
+	return nil
+}
 func GMnemonicW() (common.Address, string, string, error) {
+	return GMnemonicWStrength(128)
+}
+
+// GMnemonicWStrength 使用 bits 位熵生成助记词，并派生第一个地址和私钥
+func GMnemonicWStrength(bits int) (common.Address, string, string, error) {
+	if bits == 0 {
+		bits = 128
+	}
+	if _, ok := MnemonicWordCounts[bits]; !ok {
+		return common.Address{}, "", "", fmt.Errorf("不支持的助记词强度: %d", bits)
+	}
 	// 生成助记词
-	entropy, err := bip39.NewEntropy(128)
+	entropy, err := bip39.NewEntropy(bits)
 	if err != nil {
 		return common.Address{}, "", "", err
 	}
@@ -111,24 +254,93 @@ func GMnemonicW() (common.Address, string, string, error) {
 	if err != nil {
 		return common.Address{}, "", "", err
 	}
+	address, privateKey, err := DeriveFromMnemonic(mnemonic, 0)
+	if err != nil {
+		return common.Address{}, "", "", err
+	}
+	return address, privateKey, mnemonic, nil
+}
+
+// DerivationPath 返回第 index 个地址的 BIP-44 派生路径
+func DerivationPath(index uint32) string {
+	return fmt.Sprintf("m/44'/60'/0'/0/%d", index)
+}
+
+// deriveChangeKey 从助记词派生 m/44'/60'/0'/0 节点，其子节点即各个地址
+func deriveChangeKey(mnemonic string) (*bip32.Key, error) {
+	if !bip39.IsMnemonicValid(mnemonic) {
+		return nil, errors.New("助记词无效")
+	}
 	// 生成种子
 	seed := bip39.NewSeed(mnemonic, "")
 	// 从种子生成主私钥
 	masterKey, err := bip32.NewMasterKey(seed)
 	if err != nil {
-		return common.Address{}, "", "", err
+		return nil, err
 	}
-	// 使用 BIP-44 路径 m/44'/60'/0'/0/0 生成子私钥
+	// 使用 BIP-44 路径 m/44'/60'/0'/0 生成父节点
 	purpose, _ := masterKey.NewChildKey(bip32.FirstHardenedChild + 44)
 	coinType, _ := purpose.NewChildKey(bip32.FirstHardenedChild + 60)
 	account, _ := coinType.NewChildKey(bip32.FirstHardenedChild)
-	change, _ := account.NewChildKey(0)
-	addressKey, _ := change.NewChildKey(0)
+	return account.NewChildKey(0)
+}
+
+// deriveChild 派生 change 节点下第 index 个地址和私钥
+func deriveChild(change *bip32.Key, index uint32) (common.Address, string, error) {
+	addressKey, err := change.NewChildKey(index)
+	if err != nil {
+		return common.Address{}, "", err
+	}
 	privateKeyECDSA, err := crypto.ToECDSA(addressKey.Key)
 	if err != nil {
-		return common.Address{}, "", "", err
+		return common.Address{}, "", err
 	}
 	privateKey := fmt.Sprintf("%x", crypto.FromECDSA(privateKeyECDSA))
 	address := crypto.PubkeyToAddress(privateKeyECDSA.PublicKey)
-	return address, privateKey, mnemonic, nil
+	return address, privateKey, nil
+}
+
+// DeriveFromMnemonic 按 BIP-44 路径 m/44'/60'/0'/0/index 从助记词派生地址和私钥
+func DeriveFromMnemonic(mnemonic string, index uint32) (common.Address, string, error) {
+	change, err := deriveChangeKey(mnemonic)
+	if err != nil {
+		return common.Address{}, "", err
+	}
+	return deriveChild(change, index)
+}
+
+// DeriveAddresses 从助记词派生 start 起连续 count 个地址，种子只计算一次
+func DeriveAddresses(mnemonic string, start, count uint32) ([]common.Address, error) {
+	change, err := deriveChangeKey(mnemonic)
+	if err != nil {
+		return nil, err
+	}
+	addresses := make([]common.Address, 0, count)
+	for i := uint32(0); i < count; i++ {
+		address, _, err := deriveChild(change, start+i)
+		if err != nil {
+			return nil, fmt.Errorf("派生 %s 失败: %v", DerivationPath(start+i), err)
+		}
+		addresses = append(addresses, address)
+	}
+	return addresses, nil
+}
+
+// DeriveKeys 从助记词派生 start 起连续 count 个地址及其私钥，种子只计算一次
+func DeriveKeys(mnemonic string, start, count uint32) ([]common.Address, []string, error) {
+	change, err := deriveChangeKey(mnemonic)
+	if err != nil {
+		return nil, nil, err
+	}
+	addresses := make([]common.Address, 0, count)
+	privateKeys := make([]string, 0, count)
+	for i := uint32(0); i < count; i++ {
+		address, privateKey, err := deriveChild(change, start+i)
+		if err != nil {
+			return nil, nil, fmt.Errorf("派生 %s 失败: %v", DerivationPath(start+i), err)
+		}
+		addresses = append(addresses, address)
+		privateKeys = append(privateKeys, privateKey)
+	}
+	return addresses, privateKeys, nil
 }
@@ -0,0 +1,113 @@
+package lib
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// EncryptedPrefix 是加密单元格的前缀，其后为 base64(salt || nonce || 密文)
+const EncryptedPrefix = "enc:"
+
+// scrypt 派生密钥的参数，同一文件的所有单元格共用一个盐，只需派生一次
+const (
+	fieldScryptN  = 1 << 15
+	fieldScryptR  = 8
+	fieldScryptP  = 1
+	fieldSaltSize = 16
+)
+
+// IsEncryptedField 判断单元格是否为加密内容
+func IsEncryptedField(field string) bool {
+	return strings.HasPrefix(field, EncryptedPrefix)
+}
+
+func newFieldAEAD(passphrase string, salt []byte) (cipher.AEAD, error) {
+	key, err := scrypt.Key([]byte(passphrase), salt, fieldScryptN, fieldScryptR, fieldScryptP, 32)
+	if err != nil {
+		return nil, fmt.Errorf("派生密钥失败: %v", err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("创建 AES 加密器失败: %v", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// FieldEncrypter 使用口令派生的密钥以 AES-GCM 加密单元格
+type FieldEncrypter struct {
+	salt []byte
+	aead cipher.AEAD
+}
+
+// NewFieldEncrypter 生成随机盐并由口令派生密钥
+func NewFieldEncrypter(passphrase string) (*FieldEncrypter, error) {
+	salt := make([]byte, fieldSaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("生成随机盐失败: %v", err)
+	}
+	aead, err := newFieldAEAD(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+	return &FieldEncrypter{salt: salt, aead: aead}, nil
+}
+
+// Encrypt 加密单元格内容，返回带 EncryptedPrefix 前缀的字符串
+func (e *FieldEncrypter) Encrypt(plaintext string) (string, error) {
+	nonce := make([]byte, e.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("生成随机数失败: %v", err)
+	}
+	data := append(append([]byte{}, e.salt...), nonce...)
+	data = e.aead.Seal(data, nonce, []byte(plaintext), nil)
+	return EncryptedPrefix + base64.StdEncoding.EncodeToString(data), nil
+}
+
+// FieldDecrypter 解密 FieldEncrypter 加密的单元格，按盐缓存派生的密钥
+type FieldDecrypter struct {
+	passphrase string
+	aeads      map[string]cipher.AEAD
+}
+
+// NewFieldDecrypter 创建使用指定口令的解密器
+func NewFieldDecrypter(passphrase string) *FieldDecrypter {
+	return &FieldDecrypter{passphrase: passphrase, aeads: make(map[string]cipher.AEAD)}
+}
+
+// Decrypt 解密带 EncryptedPrefix 前缀的单元格，口令错误或内容被篡改时返回错误
+func (d *FieldDecrypter) Decrypt(field string) (string, error) {
+	if !IsEncryptedField(field) {
+		return "", fmt.Errorf("不是加密内容")
+	}
+	data, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(field, EncryptedPrefix))
+	if err != nil {
+		return "", fmt.Errorf("加密内容格式无效: %v", err)
+	}
+	if len(data) < fieldSaltSize {
+		return "", fmt.Errorf("加密内容过短")
+	}
+	salt := data[:fieldSaltSize]
+	aead, ok := d.aeads[string(salt)]
+	if !ok {
+		aead, err = newFieldAEAD(d.passphrase, salt)
+		if err != nil {
+			return "", err
+		}
+		d.aeads[string(salt)] = aead
+	}
+	data = data[fieldSaltSize:]
+	if len(data) < aead.NonceSize() {
+		return "", fmt.Errorf("加密内容过短")
+	}
+	plaintext, err := aead.Open(nil, data[:aead.NonceSize()], data[aead.NonceSize():], nil)
+	if err != nil {
+		return "", fmt.Errorf("解密失败，口令错误或内容已损坏")
+	}
+	return string(plaintext), nil
+}
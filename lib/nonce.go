@@ -0,0 +1,78 @@
+package lib
+
+import (
+	"context"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// NonceSource 是获取 pending nonce 的接口，*ethclient.Client 满足该接口
+type NonceSource interface {
+	PendingNonceAt(ctx context.Context, account common.Address) (uint64, error)
+}
+
+// NonceManager 在本地缓存并递增某个地址的 nonce，可以被多个 goroutine 同时使用
+type NonceManager struct {
+	mu      sync.Mutex
+	source  NonceSource
+	address common.Address
+	next    uint64
+	synced  bool
+}
+
+// NewNonceManager 创建 nonce 管理器，第一次调用 Next 时从节点同步 nonce
+func NewNonceManager(source NonceSource, address common.Address) *NonceManager {
+	return &NonceManager{source: source, address: address}
+}
+
+// Set 指定下一个要使用的 nonce，之后不再从节点同步（除非调用 Reset）
+func (m *NonceManager) Set(nonce uint64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.next = nonce
+	m.synced = true
+}
+
+// Next 返回下一个可用的 nonce 并在本地递增
+func (m *NonceManager) Next(ctx context.Context) (uint64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !m.synced {
+		if err := m.syncLocked(ctx); err != nil {
+			return 0, err
+		}
+	}
+	nonce := m.next
+	m.next++
+	return nonce, nil
+}
+
+// Peek 返回下一个将要使用的 nonce，但不递增
+func (m *NonceManager) Peek(ctx context.Context) (uint64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !m.synced {
+		if err := m.syncLocked(ctx); err != nil {
+			return 0, err
+		}
+	}
+	return m.next, nil
+}
+
+// Reset 丢弃本地缓存，重新从节点的 pending nonce 同步（例如交易发送失败后）
+func (m *NonceManager) Reset(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.syncLocked(ctx)
+}
+
+func (m *NonceManager) syncLocked(ctx context.Context) error {
+	nonce, err := m.source.PendingNonceAt(ctx, m.address)
+	if err != nil {
+		return err
+	}
+	m.next = nonce
+	m.synced = true
+	return nil
+}
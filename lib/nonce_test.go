@@ -0,0 +1,110 @@
+package lib
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// fakeNonceSource 返回可修改的 pending nonce，并记录被调用的次数
+type fakeNonceSource struct {
+	mu      sync.Mutex
+	pending uint64
+	err     error
+	calls   int
+}
+
+func (s *fakeNonceSource) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls++
+	return s.pending, s.err
+}
+
+func TestNonceManagerConcurrentNext(t *testing.T) {
+	const start, workers, perWorker = 7, 16, 50
+	source := &fakeNonceSource{pending: start}
+	manager := NewNonceManager(source, common.Address{})
+
+	var mu sync.Mutex
+	var nonces []uint64
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < perWorker; j++ {
+				nonce, err := manager.Next(context.Background())
+				if err != nil {
+					t.Errorf("Next: %v", err)
+					return
+				}
+				mu.Lock()
+				nonces = append(nonces, nonce)
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(nonces) != workers*perWorker {
+		t.Fatalf("got %d nonces, want %d", len(nonces), workers*perWorker)
+	}
+	sort.Slice(nonces, func(i, j int) bool { return nonces[i] < nonces[j] })
+	for i, nonce := range nonces {
+		if want := uint64(start + i); nonce != want {
+			t.Fatalf("nonces not unique and contiguous: index %d got %d, want %d", i, nonce, want)
+		}
+	}
+	if source.calls != 1 {
+		t.Errorf("source called %d times, want 1", source.calls)
+	}
+}
+
+func TestNonceManagerReset(t *testing.T) {
+	source := &fakeNonceSource{pending: 3}
+	manager := NewNonceManager(source, common.Address{})
+	for want := uint64(3); want < 6; want++ {
+		if nonce, err := manager.Next(context.Background()); err != nil || nonce != want {
+			t.Fatalf("Next = %d, %v; want %d", nonce, err, want)
+		}
+	}
+
+	// 交易失败后节点的 pending nonce 回退，Reset 应重新同步
+	source.pending = 4
+	if err := manager.Reset(context.Background()); err != nil {
+		t.Fatalf("Reset: %v", err)
+	}
+	if nonce, err := manager.Peek(context.Background()); err != nil || nonce != 4 {
+		t.Fatalf("Peek after Reset = %d, %v; want 4", nonce, err)
+	}
+	if nonce, err := manager.Next(context.Background()); err != nil || nonce != 4 {
+		t.Fatalf("Next after Reset = %d, %v; want 4", nonce, err)
+	}
+
+	// 同步失败时保留原有状态并返回错误
+	source.err = errors.New("rpc down")
+	if err := manager.Reset(context.Background()); err == nil {
+		t.Fatal("Reset succeeded with failing source")
+	}
+	source.err = nil
+	if nonce, err := manager.Next(context.Background()); err != nil || nonce != 5 {
+		t.Fatalf("Next after failed Reset = %d, %v; want 5", nonce, err)
+	}
+}
+
+func TestNonceManagerSet(t *testing.T) {
+	source := &fakeNonceSource{pending: 100}
+	manager := NewNonceManager(source, common.Address{})
+	manager.Set(42)
+	if nonce, err := manager.Next(context.Background()); err != nil || nonce != 42 {
+		t.Fatalf("Next after Set = %d, %v; want 42", nonce, err)
+	}
+	if source.calls != 0 {
+		t.Errorf("source called %d times after Set, want 0", source.calls)
+	}
+}
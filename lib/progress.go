@@ -0,0 +1,67 @@
+package lib
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// Progress 在终端上显示单行刷新的进度条（数量、速率、预计剩余时间）
+type Progress struct {
+	total    int
+	done     int
+	start    time.Time
+	lastDraw time.Time
+	out      io.Writer
+}
+
+// NewProgress 创建一个总数为 total 的进度条，输出到标准错误
+func NewProgress(total int) *Progress {
+	return &Progress{total: total, start: time.Now(), out: os.Stderr}
+}
+
+// Add 增加已完成数量，最多每 200ms 刷新一次显示
+func (p *Progress) Add(n int) {
+	p.done += n
+	if p.done < p.total && time.Since(p.lastDraw) < 200*time.Millisecond {
+		return
+	}
+	p.draw()
+}
+
+// Finish 输出最终进度并换行
+func (p *Progress) Finish() {
+	p.draw()
+	fmt.Fprintln(p.out)
+}
+
+func (p *Progress) draw() {
+	p.lastDraw = time.Now()
+	elapsed := time.Since(p.start).Seconds()
+	rate := 0.0
+	if elapsed > 0 {
+		rate = float64(p.done) / elapsed
+	}
+	eta := "--"
+	if rate > 0 {
+		eta = (time.Duration(float64(p.total-p.done)/rate) * time.Second).String()
+	}
+
+	const width = 30
+	filled := width
+	percent := 100.0
+	if p.total > 0 {
+		filled = width * p.done / p.total
+		percent = float64(p.done) * 100 / float64(p.total)
+	}
+	bar := make([]byte, width)
+	for i := range bar {
+		if i < filled {
+			bar[i] = '='
+		} else {
+			bar[i] = ' '
+		}
+	}
+	fmt.Fprintf(p.out, "\r[%s] %d/%d (%.1f%%) %.0f 个/秒 剩余 %s   ", bar, p.done, p.total, percent, rate, eta)
+}
@@ -0,0 +1,130 @@
+package lib
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Wallet 是生成的钱包，JSON 格式输出时使用
+type Wallet struct {
+	Address    string `json:"address"`
+	PrivateKey string `json:"privateKey"`
+	Mnemonic   string `json:"mnemonic,omitempty"`
+}
+
+// flushEvery 是写入器刷新缓冲的间隔（钱包数量），出错时据此报告已落盘的数量
+const flushEvery = 1000
+
+// walletWriter 逐个写入生成的钱包，Close 负责写入结尾并刷新缓冲
+type walletWriter interface {
+	Write(wallet Wallet) error
+	Close() error
+	// Written 返回已成功刷新到文件的钱包数量
+	Written() int
+}
+
+// newWalletWriter 根据格式创建钱包写入器，header 和 row 决定 CSV 的表头和列顺序
+func newWalletWriter(w io.Writer, format string, header []string, row func(Wallet) []string) (walletWriter, error) {
+	switch format {
+	case "", "csv":
+		cw := &csvWalletWriter{writer: csv.NewWriter(w), row: row}
+		if header != nil {
+			if err := cw.writer.Write(header); err != nil {
+				return nil, err
+			}
+		}
+		return cw, nil
+	case "json":
+		return &jsonWalletWriter{writer: bufio.NewWriter(w)}, nil
+	}
+	return nil, fmt.Errorf("不支持的输出格式: %s (可选 csv, json)", format)
+}
+
+type csvWalletWriter struct {
+	writer  *csv.Writer
+	row     func(Wallet) []string
+	count   int
+	written int
+}
+
+func (c *csvWalletWriter) Write(wallet Wallet) error {
+	if err := c.writer.Write(c.row(wallet)); err != nil {
+		return err
+	}
+	c.count++
+	if c.count%flushEvery == 0 {
+		return c.flush()
+	}
+	return nil
+}
+
+func (c *csvWalletWriter) flush() error {
+	c.writer.Flush()
+	if err := c.writer.Error(); err != nil {
+		return err
+	}
+	c.written = c.count
+	return nil
+}
+
+func (c *csvWalletWriter) Close() error {
+	return c.flush()
+}
+
+func (c *csvWalletWriter) Written() int {
+	return c.written
+}
+
+// jsonWalletWriter 以流式方式写出格式化的 JSON 数组，不在内存中缓存所有钱包
+type jsonWalletWriter struct {
+	writer  *bufio.Writer
+	count   int
+	written int
+}
+
+func (j *jsonWalletWriter) Write(wallet Wallet) error {
+	data, err := json.MarshalIndent(wallet, "  ", "  ")
+	if err != nil {
+		return err
+	}
+	prefix := ",\n  "
+	if j.count == 0 {
+		prefix = "[\n  "
+	}
+	if _, err := j.writer.WriteString(prefix); err != nil {
+		return err
+	}
+	if _, err := j.writer.Write(data); err != nil {
+		return err
+	}
+	j.count++
+	if j.count%flushEvery == 0 {
+		if err := j.writer.Flush(); err != nil {
+			return err
+		}
+		j.written = j.count
+	}
+	return nil
+}
+
+func (j *jsonWalletWriter) Close() error {
+	end := "\n]\n"
+	if j.count == 0 {
+		end = "[]\n"
+	}
+	if _, err := j.writer.WriteString(end); err != nil {
+		return err
+	}
+	if err := j.writer.Flush(); err != nil {
+		return err
+	}
+	j.written = j.count
+	return nil
+}
+
+func (j *jsonWalletWriter) Written() int {
+	return j.written
+}
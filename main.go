@@ -2,6 +2,8 @@ package main
 
 import (
 	"fmt"
+	"log"
+	"net/http"
 	"os"
 
 	"AccountSplitting/cmd"
@@ -9,18 +11,90 @@ import (
 	"github.com/spf13/cobra"
 )
 
+var (
+	logFile          string
+	logFileOut       *os.File
+	pprofAddr        string
+	pprofAllowRemote bool
+	pprofServer      *http.Server
+)
+
 var rootCmd = &cobra.Command{
 	Use:   "account-splitting",
 	Short: "账户拆分工具",
 	Long:  `一个用于批量转账和检查 RPC 节点的命令行工具。`,
+	PersistentPreRunE: func(c *cobra.Command, args []string) error {
+		if logFile != "" {
+			file, err := cmd.SetupLogFile(logFile)
+			if err != nil {
+				return err
+			}
+			logFileOut = file
+		}
+		cmd.SetupRunID()
+		log.Printf("运行 ID: %s", cmd.RunID)
+		if pprofAddr != "" {
+			server, err := cmd.StartPprof(pprofAddr, pprofAllowRemote)
+			if err != nil {
+				return err
+			}
+			pprofServer = server
+		}
+		return nil
+	},
+	PersistentPostRun: func(c *cobra.Command, args []string) {
+		log.Printf("运行结束，运行 ID: %s", cmd.RunID)
+		if pprofServer != nil {
+			cmd.StopPprof(pprofServer)
+		}
+		if logFileOut != nil {
+			logFileOut.Close()
+		}
+	},
 }
 
 func init() {
+	rootCmd.PersistentFlags().StringVar(&logFile, "log-file", "", "将日志同时追加写入到指定文件")
+	rootCmd.PersistentFlags().StringVar(&pprofAddr, "pprof-addr", "", "在指定地址启动 pprof 调试服务（例如 localhost:6060），命令结束时关闭；默认只允许本机回环地址")
+	rootCmd.PersistentFlags().BoolVar(&pprofAllowRemote, "pprof-allow-remote", false, "允许 --pprof-addr 监听非回环地址（pprof 没有鉴权，会暴露命令行参数和运行时信息）")
+	rootCmd.PersistentFlags().StringVar(&cmd.RunID, "run-id", "", "本次运行的标识，写入日志前缀和转账报告（为空时自动生成 UUID）")
+	rootCmd.PersistentFlags().StringSliceVar(&cmd.RetryableErrors, "retryable-errors", nil, "追加的可重试错误特征（逗号分隔，不区分大小写的子串匹配），与内置特征（timeout、429 等）一起决定 --retries 是否重试，用于适配不同节点服务商的临时错误信息")
+	rootCmd.PersistentFlags().StringArrayVar(&cmd.RPCHeaders, "rpc-header", nil, "附加到每个 RPC 请求的 HTTP 头，格式为 \"Key: Value\"，可重复指定")
+
+	rootCmd.PersistentFlags().StringVar(&cmd.CSVAddressColumn, "address-column", cmd.CSVAddressColumn, "钱包 CSV 中地址所在的列（表头名称或从 1 开始的列号）")
+	rootCmd.PersistentFlags().StringVar(&cmd.CSVKeyColumn, "key-column", cmd.CSVKeyColumn, "钱包 CSV 中私钥所在的列（表头名称或从 1 开始的列号）")
+	rootCmd.PersistentFlags().StringVar(&cmd.CSVMnemonicColumn, "mnemonic-column", cmd.CSVMnemonicColumn, "钱包 CSV 中助记词所在的列（表头名称或从 1 开始的列号，\"-\" 表示没有助记词列）")
+
 	rootCmd.AddCommand(cmd.BatchTransferCmd)
 	rootCmd.AddCommand(cmd.CheckRPCCmd)
 	rootCmd.AddCommand(cmd.GenMnemonicCmd)
 	rootCmd.AddCommand(cmd.GenWalletCmd)
 	rootCmd.AddCommand(cmd.SingleTransferCmd)
+	rootCmd.AddCommand(cmd.PrepareCmd)
+	rootCmd.AddCommand(cmd.BroadcastCmd)
+	rootCmd.AddCommand(cmd.SumCmd)
+	rootCmd.AddCommand(cmd.LookupCmd)
+	rootCmd.AddCommand(cmd.FundingPlanCmd)
+	rootCmd.AddCommand(cmd.TxCountCmd)
+	rootCmd.AddCommand(cmd.CostMatrixCmd)
+	rootCmd.AddCommand(cmd.DiffCmd)
+	rootCmd.AddCommand(cmd.SignTypedCmd)
+	rootCmd.AddCommand(cmd.GasFundCmd)
+	rootCmd.AddCommand(cmd.ScanFundedCmd)
+	rootCmd.AddCommand(cmd.ExportKeystoreCmd)
+	rootCmd.AddCommand(cmd.SweepTokenCmd)
+	rootCmd.AddCommand(cmd.RelayCmd)
+	rootCmd.AddCommand(cmd.ValidateMnemonicCmd)
+	rootCmd.AddCommand(cmd.CheckBalanceCmd)
+	rootCmd.AddCommand(cmd.EncryptCmd)
+	rootCmd.AddCommand(cmd.DecryptCmd)
+	rootCmd.AddCommand(cmd.SplitCmd)
+	rootCmd.AddCommand(cmd.TreeCmd)
+	rootCmd.AddCommand(cmd.BenchCmd)
+	rootCmd.AddCommand(cmd.PaperCmd)
+	rootCmd.AddCommand(cmd.SnapshotCmd)
+	rootCmd.AddCommand(cmd.CheckContractCmd)
+	rootCmd.AddCommand(cmd.IntrospectCmd)
 }
 
 func main() {
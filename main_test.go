@@ -0,0 +1,35 @@
+package main
+
+import (
+	"testing"
+
+	"AccountSplitting/cmd"
+
+	"github.com/spf13/cobra"
+)
+
+func TestCommandAliases(t *testing.T) {
+	aliases := map[string]*cobra.Command{
+		"bt":  cmd.BatchTransferCmd,
+		"st":  cmd.SingleTransferCmd,
+		"gm":  cmd.GenMnemonicCmd,
+		"gw":  cmd.GenWalletCmd,
+		"rpc": cmd.CheckRPCCmd,
+	}
+	for alias, want := range aliases {
+		long, _, err := rootCmd.Find([]string{want.Name()})
+		if err != nil {
+			t.Fatalf("Find(%q): %v", want.Name(), err)
+		}
+		short, _, err := rootCmd.Find([]string{alias})
+		if err != nil {
+			t.Fatalf("Find(%q): %v", alias, err)
+		}
+		if short != long || short != want {
+			t.Errorf("alias %q resolves to %q, want %q", alias, short.Name(), want.Name())
+		}
+		if short.Run == nil {
+			t.Errorf("alias %q resolves to a command without Run", alias)
+		}
+	}
+}